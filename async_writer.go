@@ -15,7 +15,9 @@
 package slago
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 )
 
 const defaultWriterQueueSize = 256
@@ -23,8 +25,11 @@ const defaultWriterQueueSize = 256
 type asyncWriter struct {
 	ref       Writer
 	locker    sync.Mutex
+	queueSize int
 	queue     *blockingQueue
 	isStarted bool
+	pending   int64
+	guard     writingGuard
 }
 
 // AsyncWriterOption represents available options for async writer.
@@ -44,45 +49,74 @@ func NewAsyncWriter(options ...func(*AsyncWriterOption)) Writer {
 	}
 
 	return &asyncWriter{
-		ref:   opt.Ref,
-		queue: NewBlockingQueue(opt.QueueSize),
+		ref:       opt.Ref,
+		queueSize: opt.QueueSize,
 	}
 }
 
-func (w *asyncWriter) Start() {
+func (w *asyncWriter) Start() error {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+
 	if w.isStarted {
-		return
+		return nil
 	}
 	if lc, ok := w.ref.(Lifecycle); ok {
-		lc.Start()
+		if err := lc.Start(); err != nil {
+			return err
+		}
 	}
+	// a new queue is used in each start, so the worker of last start won't
+	// take events from this queue
+	w.queue = NewBlockingQueue(w.queueSize)
 	w.isStarted = true
-	go w.startWorker()
+	go w.startWorker(w.queue)
+
+	return nil
 }
 
 func (w *asyncWriter) Stop() {
 	w.locker.Lock()
 	defer w.locker.Unlock()
+
+	if !w.isStarted {
+		return
+	}
+	w.isStarted = false
+	// wake up the worker, and the events left won't be written
+	discarded := w.queue.Close()
+	atomic.AddInt64(&w.pending, -int64(discarded))
 	if lc, ok := w.ref.(Lifecycle); ok {
 		lc.Stop()
 	}
-	w.isStarted = false
 }
 
 func (w *asyncWriter) Write(p []byte) (n int, err error) {
+	// the events emitted in worker goroutine will be written again and again
+	if w.guard.recursive(asyncWriterEntry) {
+		dropRecursive(p)
+		return len(p), nil
+	}
+
 	w.locker.Lock()
 	defer w.locker.Unlock()
 
-	if w.queue.RemainCapacity() <= 16 {
+	if !w.isStarted || w.queue.RemainCapacity() <= 16 {
 		// discard
 		return 0, nil
 	}
 
+	atomic.AddInt64(&w.pending, 1)
 	w.queue.Put(p)
 
 	return len(p), nil
 }
 
+// Drain waits until all the events in queue are written.
+func (w *asyncWriter) Drain(ctx context.Context) error {
+	return drain(ctx, &w.pending)
+}
+
 func (w *asyncWriter) Encoder() Encoder {
 	return nil
 }
@@ -91,18 +125,25 @@ func (w *asyncWriter) Filter() Filter {
 	return nil
 }
 
-func (w *asyncWriter) startWorker() {
+// startWorker writes the events in queue until the queue is closed.
+func (w *asyncWriter) startWorker(queue *blockingQueue) {
 	for {
-		if !w.isStarted {
-			break
+		p, ok := queue.Take()
+		if !ok {
+			return
 		}
-
-		p := w.queue.Take()
 		w.write(p)
+		atomic.AddInt64(&w.pending, -1)
 	}
 }
 
+// write writes the event into the referenced writer in worker goroutine.
+//
+//go:noinline
 func (w *asyncWriter) write(p []byte) {
+	w.guard.enter()
+	defer w.guard.exit()
+
 	event := makeEvent(p)
 	defer event.recycle()
 
@@ -115,13 +156,13 @@ func (w *asyncWriter) write(p []byte) {
 	if w.ref.Encoder() != nil {
 		encoded, err = w.ref.Encoder().Encode(event)
 		if err != nil {
-			Reportf("async writer encode error: %v", err)
+			defaultStatusManager.Error("async writer", "encode error: %v", err)
 			return
 		}
 	}
 
 	_, err = w.ref.Write(encoded)
 	if err != nil {
-		Reportf("async writer write error: %v", err)
+		defaultStatusManager.Error("async writer", "write error: %v", err)
 	}
 }
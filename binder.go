@@ -0,0 +1,182 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	// nativeOnce reports the fallback to native logger only once.
+	nativeOnce sync.Once
+
+	// binderOwners records the context which each binder is bound to, since
+	// the state of binder such as writers and level can't be shared.
+	ownerLocker  sync.Mutex
+	binderOwners = make(map[SlaLogger]*LoggerContext)
+)
+
+// UseBinder selects the binder with given name in this context, which is
+// useful when multiple binders are bound. The name can be the full name of
+// binder or the last element of it, such as go.uber.org/zap or zap. An error
+// will be returned if the binder conflicts with installed bridges. This must
+// be called before any logger is used, and SLAGO_BINDER overrides it.
+func (lc *LoggerContext) UseBinder(name string) error {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	old := lc.binder
+	lc.binder = name
+	if err := lc.checkBinders(); err != nil {
+		lc.binder = old
+		return err
+	}
+
+	return nil
+}
+
+// Binders gets the names of all bound binders in this context.
+func (lc *LoggerContext) Binders() []string {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	names := make([]string, 0, len(lc.loggers))
+	for _, logger := range lc.loggers {
+		names = append(names, logger.Name())
+	}
+
+	return names
+}
+
+// binderName gets the name of selected binder, the environment variable
+// overrides the name selected by UseBinder.
+func (lc *LoggerContext) binderName() string {
+	if name := os.Getenv(BinderEnvKey); len(name) != 0 {
+		return name
+	}
+
+	return lc.binder
+}
+
+// selectBinder selects the binder used in this context. The binder with
+// selected name is preferred, or the first one which doesn't conflict with
+// installed bridges. The native logger will be used if no binder found.
+func (lc *LoggerContext) selectBinder() SlaLogger {
+	name := lc.binderName()
+	if len(name) != 0 {
+		logger := lc.namedBinder(name)
+		if logger == nil {
+			defaultStatusManager.Error("logger context", "binder %s not found", name)
+		} else if err := lc.checkCycle(logger); err != nil {
+			defaultStatusManager.Error("logger context", "%v", err)
+		} else {
+			return logger
+		}
+	}
+
+	logger := lc.defaultBinder()
+	if logger == nil {
+		nativeOnce.Do(func() {
+			defaultStatusManager.Info("logger context",
+				"no slago logger found, default to native logger implementation")
+		})
+		return NewNativeLogger()
+	}
+
+	if len(name) == 0 && len(lc.loggers) > 1 {
+		defaultStatusManager.Warn("logger context", "multiple slago logger implementation "+
+			"found, %s is used, use UseBinder or %s to select one", logger.Name(), BinderEnvKey)
+	}
+
+	return logger
+}
+
+// checkBinders checks if any bound binder conflicts with installed bridges,
+// each binder is checked on its own no matter which one will be selected.
+func (lc *LoggerContext) checkBinders() error {
+	for _, logger := range lc.loggers {
+		if err := lc.checkCycle(logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// namedBinder finds the binder with given name, nil will be returned if not found.
+func (lc *LoggerContext) namedBinder(name string) SlaLogger {
+	if len(name) == 0 {
+		return nil
+	}
+
+	for _, logger := range lc.loggers {
+		if logger.Name() == name || afterLastSlash(logger.Name()) == name {
+			return logger
+		}
+	}
+
+	return nil
+}
+
+// defaultBinder finds the first binder which doesn't conflict with installed bridges.
+func (lc *LoggerContext) defaultBinder() SlaLogger {
+	for _, logger := range lc.loggers {
+		if lc.checkCycle(logger) == nil {
+			return logger
+		}
+	}
+
+	return nil
+}
+
+// checkCycle checks if the binder is the same logging framework with any
+// installed bridge, which will cause cycle logging.
+func (lc *LoggerContext) checkCycle(logger SlaLogger) error {
+	for _, b := range lc.bridges {
+		if logger.Name() == b.Name() {
+			return fmt.Errorf("cycle logger checked, %s -> slago -> %s", b.Name(), logger.Name())
+		}
+	}
+
+	return nil
+}
+
+// claimBinder makes the binder owned by the given context, an error will be
+// returned if the binder has been bound to another context.
+func claimBinder(lc *LoggerContext, logger SlaLogger) error {
+	ownerLocker.Lock()
+	defer ownerLocker.Unlock()
+
+	if owner, ok := binderOwners[logger]; ok && owner != lc {
+		return fmt.Errorf("binder %s has been bound to another logger context", logger.Name())
+	}
+	binderOwners[logger] = lc
+
+	return nil
+}
+
+// releaseBinders releases all the binders owned by the given context.
+func releaseBinders(lc *LoggerContext) {
+	ownerLocker.Lock()
+	defer ownerLocker.Unlock()
+
+	for logger, owner := range binderOwners {
+		if owner == lc {
+			delete(binderOwners, logger)
+		}
+	}
+}
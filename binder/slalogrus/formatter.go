@@ -16,12 +16,16 @@ package slalogrus
 
 import (
 	"bytes"
-	"sync"
+	"encoding/json"
+	"fmt"
 
 	"github.com/coolerfall/slago"
 	"github.com/sirupsen/logrus"
 )
 
+// levelNameKey is the key of field which holds the name of custom level.
+const levelNameKey = "slago.level"
+
 var (
 	logrusLvlToSlagoLvl = map[logrus.Level]slago.Level{
 		logrus.TraceLevel: slago.TraceLevel,
@@ -34,34 +38,36 @@ var (
 	}
 )
 
-type transformer struct {
-	buf         *bytes.Buffer
-	locker      sync.Mutex
-	multiWriter *slago.MultiWriter
-}
+// formatter formats logrus entry into json with the level name of slago.
+type formatter struct{}
 
-func newTransformer(w *slago.MultiWriter) *transformer {
-	return &transformer{
-		buf:         new(bytes.Buffer),
-		multiWriter: w,
+func (f *formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+3)
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			// errors are ignored by encoding/json
+			v = err.Error()
+		}
+		data[k] = v
 	}
-}
 
-func (t *transformer) Write(p []byte) (n int, err error) {
-	t.locker.Lock()
-	defer t.locker.Unlock()
+	level, ok := data[levelNameKey]
+	if ok {
+		delete(data, levelNameKey)
+	} else {
+		level = logrusLvlToSlagoLvl[entry.Level].String()
+	}
+	data[slago.LevelFieldKey] = level
+	data[slago.TimestampFieldKey] = entry.Time.Format(slago.TimestampFormat)
+	data[slago.MessageFieldKey] = entry.Message
 
-	_ = slago.ReplaceJson(p, t.buf, slago.LevelFieldKey,
-		func(k, v []byte) ([]byte, []byte, error) {
-			lvl, err := logrus.ParseLevel(string(v))
-			if err != nil {
-				return k, v, err
-			} else {
-				return k, []byte(logrusLvlToSlagoLvl[lvl].String()), nil
-			}
-		})
-	p = t.buf.Bytes()
-	t.buf.Reset()
+	b := entry.Buffer
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+	if err := json.NewEncoder(b).Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to json: %v", err)
+	}
 
-	return t.multiWriter.Write(p)
+	return b.Bytes(), nil
 }
@@ -15,6 +15,9 @@
 package slalogrus
 
 import (
+	"context"
+	"sync/atomic"
+
 	"github.com/coolerfall/slago"
 	"github.com/sirupsen/logrus"
 )
@@ -31,32 +34,44 @@ var (
 	}
 )
 
+// levelOff turns off logging, since there's no such level in logrus.
+const levelOff int32 = -1
+
 // logrusLogger is an implementation of SlaLogger.
 type logrusLogger struct {
+	entry *logrus.Entry
+	// level is shared by the loggers created from this logger, the level
+	// of logrus logger is always trace level
+	level       *int32
 	multiWriter *slago.MultiWriter
 }
 
 // NewLogrusLogger creates a new instance of logrusLogger used to be bound to slago
 func NewLogrusLogger() slago.SlaLogger {
-	logrus.SetFormatter(&logrus.JSONFormatter{
-		TimestampFormat: slago.TimestampFormat,
-		FieldMap: logrus.FieldMap{
-			logrus.FieldKeyLevel: slago.LevelFieldKey,
-			logrus.FieldKeyTime:  slago.TimestampFieldKey,
-			logrus.FieldKeyMsg:   slago.MessageFieldKey,
-		},
-	})
-	logrus.SetLevel(logrus.TraceLevel)
-
 	writer := slago.NewMultiWriter()
-	transformer := newTransformer(writer)
-	logrus.SetOutput(transformer)
+	level := int32(logrus.TraceLevel)
 
 	return &logrusLogger{
+		entry:       logrus.NewEntry(newLogrus(writer)),
+		level:       &level,
 		multiWriter: writer,
 	}
 }
 
+// newLogrus creates a private logrus logger, so the settings won't affect
+// other logrus users.
+func newLogrus(w *slago.MultiWriter) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetFormatter(&formatter{})
+	logger.SetLevel(logrus.TraceLevel)
+	logger.SetOutput(w)
+	// the multi writer is thread safe, the lock of logrus will cause deadlock
+	// when logging inside writers
+	logger.SetNoLock()
+
+	return logger
+}
+
 func (l *logrusLogger) Name() string {
 	return "github.com/sirupsen/logrus"
 }
@@ -70,40 +85,86 @@ func (l *logrusLogger) ResetWriter() {
 }
 
 func (l *logrusLogger) SetLevel(lvl slago.Level) {
-	logrus.SetLevel(slagoLvlToLogrusLvl[lvl])
+	if lvl >= slago.OffLevel {
+		atomic.StoreInt32(l.level, levelOff)
+		return
+	}
+
+	atomic.StoreInt32(l.level, int32(slagoLvlToLogrusLvl[slago.StandardLevel(lvl)]))
+}
+
+func (l *logrusLogger) With() *slago.FieldContext {
+	r := &logrusRecord{entry: l.entry}
+	return slago.NewFieldContext(r, func() slago.SlaLogger {
+		return &logrusLogger{
+			entry:       r.entry,
+			level:       l.level,
+			multiWriter: l.multiWriter,
+		}
+	})
+}
+
+func (l *logrusLogger) Output(w slago.Writer) slago.SlaLogger {
+	multiWriter := slago.NewMultiWriter()
+	multiWriter.AddWriter(w)
+	return &logrusLogger{
+		entry:       logrus.NewEntry(newLogrus(multiWriter)).WithFields(l.entry.Data),
+		level:       l.level,
+		multiWriter: multiWriter,
+	}
+}
+
+func (l *logrusLogger) Ctx(ctx context.Context) slago.SlaLogger {
+	return l.With().Ctx(ctx).Logger()
 }
 
 func (l *logrusLogger) Trace() slago.Record {
-	return newLogrusRecord(logrus.TraceLevel)
+	return newLogrusRecord(l.entry, logrus.TraceLevel, l.level)
 }
 
 func (l *logrusLogger) Debug() slago.Record {
-	return newLogrusRecord(logrus.DebugLevel)
+	return newLogrusRecord(l.entry, logrus.DebugLevel, l.level)
 }
 
 func (l *logrusLogger) Info() slago.Record {
-	return newLogrusRecord(logrus.InfoLevel)
+	return newLogrusRecord(l.entry, logrus.InfoLevel, l.level)
+}
+
+func (l *logrusLogger) Notice() slago.Record {
+	return l.Level(slago.NoticeLevel)
 }
 
 func (l *logrusLogger) Warn() slago.Record {
-	return newLogrusRecord(logrus.WarnLevel)
+	return newLogrusRecord(l.entry, logrus.WarnLevel, l.level)
 }
 
 func (l *logrusLogger) Error() slago.Record {
-	return newLogrusRecord(logrus.ErrorLevel)
+	return newLogrusRecord(l.entry, logrus.ErrorLevel, l.level)
 }
 
 func (l *logrusLogger) Fatal() slago.Record {
-	return newLogrusRecord(logrus.FatalLevel)
+	return newLogrusRecord(l.entry, logrus.FatalLevel, l.level)
 }
 
 func (l *logrusLogger) Panic() slago.Record {
-	return newLogrusRecord(logrus.PanicLevel)
+	return newLogrusRecord(l.entry, logrus.PanicLevel, l.level)
+}
+
+func (l *logrusLogger) Level(lvl slago.Level) slago.Record {
+	std := slago.StandardLevel(lvl)
+	r := newLogrusRecord(l.entry, slagoLvlToLogrusLvl[std], l.level)
+	if std != lvl {
+		// the formatter will use the custom level name instead of native one
+		r.entry = r.entry.WithField(levelNameKey, lvl.String())
+	}
+
+	return r
 }
 
 func (l *logrusLogger) WriteRaw(p []byte) {
 	_, err := l.multiWriter.Write(p)
 	if err != nil {
-		l.Error().Err(err).Msg("write raw error")
+		// report to status manager instead of logging into the failed writer
+		slago.DefaultStatusManager().Error("logrus logger", "write raw error: %v", err)
 	}
 }
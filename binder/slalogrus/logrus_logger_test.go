@@ -0,0 +1,90 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slalogrus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/coolerfall/slago"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func TestLogrusLogger(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "logrus logger test")
+}
+
+// register only once since all the specs are run in every test
+var auditLevelErr = slago.RegisterLevel(35, "AUDIT", "magenta")
+
+var _ = Describe("logrus logger", func() {
+	var lc *slago.LoggerContext
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		Expect(auditLevelErr).To(BeNil())
+		lc = slago.NewLoggerContext()
+		Expect(lc.Bind(NewLogrusLogger())).To(BeNil())
+		buf = new(bytes.Buffer)
+		lc.Logger().AddWriter(&bufferWriter{buf})
+	})
+	AfterEach(func() {
+		lc.Reset()
+	})
+
+	table.DescribeTable("record",
+		func(log func(l slago.SlaLogger), expected ...string) {
+			log(lc.Logger("acme"))
+			for _, e := range expected {
+				Expect(buf.String()).To(ContainSubstring(e))
+			}
+		},
+		table.Entry("nil stringer", func(l slago.SlaLogger) {
+			l.Info().Stringer("nil_stringer", nil).Stringer("dur", time.Second).Msg("")
+		}, `"nil_stringer":null`, `"dur":"1s"`),
+		table.Entry("custom level", func(l slago.SlaLogger) {
+			l.Level(35).Msg("audit")
+		}, `"level":"AUDIT"`, `"message":"audit"`),
+		table.Entry("notice level", func(l slago.SlaLogger) {
+			l.Notice().Msg("notice")
+		}, `"level":"NOTICE"`),
+		table.Entry("timestamp", func(l slago.SlaLogger) {
+			l.Info().Timestamp(time.Date(2021, 1, 2, 3, 4, 5, 6, time.FixedZone("", 8*3600))).Msg("")
+		}, `"time":"2021-01-02T03:04:05.000000006+08:00"`),
+		table.Entry("with fields", func(l slago.SlaLogger) {
+			l.With().Str("str", "value").Logger().Warn().Msg("with")
+		}, `"str":"value"`, `"level":"WARN"`, `"logger_name":"acme"`),
+	)
+})
+
+type bufferWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *bufferWriter) Write(p []byte) (n int, err error) {
+	return w.buf.Write(p)
+}
+
+func (w *bufferWriter) Encoder() slago.Encoder {
+	return nil
+}
+
+func (w *bufferWriter) Filter() slago.Filter {
+	return nil
+}
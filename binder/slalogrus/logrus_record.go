@@ -15,8 +15,14 @@
 package slalogrus
 
 import (
+	"context"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/coolerfall/slago"
@@ -34,12 +40,15 @@ var (
 type logrusRecord struct {
 	entry *logrus.Entry
 	level logrus.Level
+	// loggerLevel is the level of the logger which creates this record
+	loggerLevel *int32
 }
 
-func newLogrusRecord(lvl logrus.Level) *logrusRecord {
+func newLogrusRecord(entry *logrus.Entry, lvl logrus.Level, loggerLevel *int32) *logrusRecord {
 	r := recordPool.Get().(*logrusRecord)
-	r.entry = logrus.NewEntry(logrus.StandardLogger())
+	r.entry = entry
 	r.level = lvl
+	r.loggerLevel = loggerLevel
 
 	return r
 }
@@ -65,8 +74,16 @@ func (r *logrusRecord) Hex(key string, val []byte) slago.Record {
 }
 
 func (r *logrusRecord) Err(err error) slago.Record {
-	r.entry = r.entry.WithError(err)
-	return r
+	if err == nil {
+		return r
+	}
+
+	r.Object(slago.ErrorFieldKey, slago.ErrorObject(err))
+	return slago.AppendErrorStack(r, err)
+}
+
+func (r *logrusRecord) Stack() slago.Record {
+	return r.Strs(slago.StackFieldKey, slago.Stack(0))
 }
 
 func (r *logrusRecord) Errs(key string, errs []error) slago.Record {
@@ -224,21 +241,272 @@ func (r *logrusRecord) Durs(key string, val []time.Duration) slago.Record {
 	return r
 }
 
+func (r *logrusRecord) RawJSON(key string, val []byte) slago.Record {
+	r.entry = r.entry.WithField(key, json.RawMessage(val))
+	return r
+}
+
+func (r *logrusRecord) Stringer(key string, val fmt.Stringer) slago.Record {
+	if val == nil {
+		r.entry = r.entry.WithField(key, nil)
+		return r
+	}
+
+	r.entry = r.entry.WithField(key, val.String())
+	return r
+}
+
+func (r *logrusRecord) IPAddr(key string, ip net.IP) slago.Record {
+	r.entry = r.entry.WithField(key, ip.String())
+	return r
+}
+
+func (r *logrusRecord) IPPrefix(key string, pfx net.IPNet) slago.Record {
+	r.entry = r.entry.WithField(key, pfx.String())
+	return r
+}
+
+func (r *logrusRecord) MACAddr(key string, ha net.HardwareAddr) slago.Record {
+	r.entry = r.entry.WithField(key, ha.String())
+	return r
+}
+
+func (r *logrusRecord) URL(key string, val *url.URL) slago.Record {
+	if val == nil {
+		r.entry = r.entry.WithField(key, nil)
+		return r
+	}
+
+	r.entry = r.entry.WithField(key, val.String())
+	return r
+}
+
+func (r *logrusRecord) Dict(key string, f func(r slago.Record)) slago.Record {
+	d := newNestedRecord()
+	f(d)
+	r.entry = r.entry.WithField(key, d.fields())
+	return r
+}
+
+func (r *logrusRecord) Object(key string, obj slago.ObjectMarshaler) slago.Record {
+	r.entry = r.entry.WithField(key, marshalObject(obj))
+	return r
+}
+
+func (r *logrusRecord) Array(key string, arr slago.ArrayMarshaler) slago.Record {
+	enc := &arrayEncoder{}
+	_ = arr.MarshalSlagoArray(enc)
+	r.entry = r.entry.WithField(key, enc.values)
+	return r
+}
+
 func (r *logrusRecord) Interface(key string, val interface{}) slago.Record {
+	switch v := val.(type) {
+	case slago.ObjectMarshaler:
+		return r.Object(key, v)
+	case slago.ArrayMarshaler:
+		return r.Array(key, v)
+	}
+
 	r.entry = r.entry.WithField(key, val)
 	return r
 }
 
+func (r *logrusRecord) Any(key string, val interface{}) slago.Record {
+	return slago.AppendAny(r, key, val)
+}
+
+func (r *logrusRecord) Fields(fields map[string]interface{}) slago.Record {
+	return slago.AppendFields(r, fields)
+}
+
+func (r *logrusRecord) Marker(names ...string) slago.Record {
+	if len(names) == 0 {
+		return r
+	}
+
+	// fields in logrus is a map, so merge with the existing markers
+	if markers, ok := r.entry.Data[slago.MarkerFieldKey].([]string); ok {
+		names = append(append([]string{}, markers...), names...)
+	}
+
+	return r.Strs(slago.MarkerFieldKey, names)
+}
+
+func (r *logrusRecord) Ctx(ctx context.Context) slago.Record {
+	return slago.ExtractContext(ctx, r)
+}
+
+func (r *logrusRecord) Func(key string, f func() interface{}) slago.Record {
+	if r.Enabled() {
+		r.Any(key, f())
+	}
+	return r
+}
+
+func (r *logrusRecord) Lazy(f func(r slago.Record)) slago.Record {
+	if r.Enabled() {
+		f(r)
+	}
+	return r
+}
+
+func (r *logrusRecord) Enabled() bool {
+	// nested record is always enabled
+	return r.entry.Logger == nil || r.levelEnabled()
+}
+
+func (r *logrusRecord) Timestamp(t time.Time) slago.Record {
+	r.entry = r.entry.WithTime(t)
+	return r
+}
+
 func (r *logrusRecord) Msg(originMsg ...string) {
+	// nested record has no logger, just ignore it
+	if r.entry.Logger == nil {
+		return
+	}
+	if !r.levelEnabled() {
+		r.write("", false)
+		return
+	}
+
+	slago.AppendCaller(r)
+
 	var msg string
 	if len(originMsg) != 0 {
 		msg = originMsg[0]
 	}
-	r.entry.Log(r.level, msg)
-	recordPool.Put(r)
+	r.write(msg, true)
 }
 
 func (r *logrusRecord) Msgf(format string, v ...interface{}) {
-	r.entry.Logf(r.level, format, v...)
+	if r.entry.Logger == nil {
+		return
+	}
+	if !r.levelEnabled() {
+		r.write("", false)
+		return
+	}
+
+	slago.AppendCaller(r)
+
+	r.write(fmt.Sprintf(format, v...), true)
+}
+
+func (r *logrusRecord) Msgt(template string, args ...interface{}) {
+	r.Msg(slago.AppendTemplate(r, template, args...))
+}
+
+// write logs the message if enabled, and terminates the process after fatal
+// or panic event is written, slago will be shut down before that.
+func (r *logrusRecord) write(msg string, enabled bool) {
+	level, entry := r.level, r.entry
+	if level > logrus.FatalLevel {
+		if enabled {
+			entry.Log(level, msg)
+		}
+		recordPool.Put(r)
+		return
+	}
+
+	// logrus panics after panic event is written, recover it to shut down first
+	var recovered interface{}
+	if enabled {
+		func() {
+			defer func() { recovered = recover() }()
+			entry.Log(level, msg)
+		}()
+	}
 	recordPool.Put(r)
+
+	slago.ShutdownBeforeExit()
+	if level == logrus.FatalLevel {
+		entry.Logger.Exit(1)
+	}
+	if recovered == nil {
+		recovered = msg
+	}
+	panic(recovered)
+}
+
+// levelEnabled checks if the level of this record is enabled in the logger.
+func (r *logrusRecord) levelEnabled() bool {
+	return r.loggerLevel == nil || int32(r.level) <= atomic.LoadInt32(r.loggerLevel)
+}
+
+// newNestedRecord creates a record without logger to collect fields of nested object.
+func newNestedRecord() *logrusRecord {
+	return &logrusRecord{
+		entry: logrus.NewEntry(nil),
+	}
+}
+
+// fields gets the collected fields, errors will be converted to string
+// since they cannot be marshaled into json directly.
+func (r *logrusRecord) fields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(r.entry.Data))
+	for k, v := range r.entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
+		fields[k] = v
+	}
+
+	return fields
+}
+
+func marshalObject(obj slago.ObjectMarshaler) map[string]interface{} {
+	d := newNestedRecord()
+	_ = obj.MarshalSlagoObject(d)
+	return d.fields()
+}
+
+// arrayEncoder collects elements of array into slice.
+type arrayEncoder struct {
+	values []interface{}
+}
+
+func (e *arrayEncoder) AppendStr(val string) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendBool(val bool) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendInt(val int) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendInt64(val int64) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendUint(val uint) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendUint64(val uint64) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendFloat64(val float64) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendTime(val time.Time) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendDur(val time.Duration) {
+	e.values = append(e.values, val)
+}
+
+func (e *arrayEncoder) AppendObject(obj slago.ObjectMarshaler) {
+	e.values = append(e.values, marshalObject(obj))
+}
+
+func (e *arrayEncoder) AppendInterface(val interface{}) {
+	e.values = append(e.values, val)
 }
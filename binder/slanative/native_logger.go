@@ -0,0 +1,26 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slanative
+
+import (
+	"github.com/coolerfall/slago"
+)
+
+// NewNativeLogger creates a new instance of native slago logger used to be bound
+// to slago, which has no dependency on any third-party logging framework. This is
+// also the default slago logger if no other slago logger is bound.
+func NewNativeLogger() slago.SlaLogger {
+	return slago.NewNativeLogger()
+}
@@ -15,6 +15,8 @@
 package slazap
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/coolerfall/slago"
@@ -32,39 +34,52 @@ var (
 		slago.FatalLevel: zapcore.FatalLevel,
 		slago.PanicLevel: zapcore.PanicLevel,
 	}
+
+	// custom levels are mapped to unused levels below debug level of zap,
+	// so the level encoder can emit the name of custom level
+	customLocker        sync.RWMutex
+	slagoLvlToCustomLvl = map[slago.Level]zapcore.Level{}
+	customLvlToSlagoLvl = map[zapcore.Level]slago.Level{}
 )
 
 // zapLogger is an implementation of SlaLogger.
 type zapLogger struct {
+	logger      *zap.Logger
 	atomicLevel zap.AtomicLevel
+	fields      []zap.Field
 	multiWriter *slago.MultiWriter
 }
 
 // NewZapLogger creates a new instance of zapLogger used to be bound to slago.
+// The global logger of zap won't be replaced.
 func NewZapLogger() slago.SlaLogger {
 	atomicLevel := zap.NewAtomicLevel()
 	atomicLevel.SetLevel(zapcore.DebugLevel)
 
+	writer := slago.NewMultiWriter()
+	return &zapLogger{
+		logger:      newZap(writer, atomicLevel),
+		atomicLevel: atomicLevel,
+		multiWriter: writer,
+	}
+}
+
+// newZap creates a zap logger which writes into the given writer.
+func newZap(w *slago.MultiWriter, atomicLevel zap.AtomicLevel) *zap.Logger {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.LevelKey = slago.LevelFieldKey
 	encoderConfig.MessageKey = slago.MessageFieldKey
 	encoderConfig.TimeKey = slago.TimestampFieldKey
 	encoderConfig.EncodeTime = rf3339Encoder
-	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	encoderConfig.EncodeLevel = capitalLevelEncoder
 
-	writer := slago.NewMultiWriter()
-	logger := zap.New(zapcore.NewCore(
+	return zap.New(zapcore.NewCore(
 		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(writer),
-		atomicLevel,
+		zapcore.AddSync(w),
+		zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return atomicLevel.Enabled(standardLevel(lvl))
+		}),
 	))
-
-	zap.ReplaceGlobals(logger)
-
-	return &zapLogger{
-		atomicLevel: atomicLevel,
-		multiWriter: writer,
-	}
 }
 
 func (l *zapLogger) Name() string {
@@ -80,7 +95,40 @@ func (l *zapLogger) ResetWriter() {
 }
 
 func (l *zapLogger) SetLevel(lvl slago.Level) {
-	l.atomicLevel.SetLevel(slagoLvlToZapLvl[lvl])
+	if lvl >= slago.OffLevel {
+		// fatal is the highest level in zap
+		l.atomicLevel.SetLevel(zapcore.FatalLevel + 1)
+		return
+	}
+
+	l.atomicLevel.SetLevel(slagoLvlToZapLvl[slago.StandardLevel(lvl)])
+}
+
+func (l *zapLogger) With() *slago.FieldContext {
+	r := &zapRecord{}
+	return slago.NewFieldContext(r, func() slago.SlaLogger {
+		return &zapLogger{
+			logger:      l.logger.With(r.fields...),
+			atomicLevel: l.atomicLevel,
+			fields:      append(l.fields[:len(l.fields):len(l.fields)], r.fields...),
+			multiWriter: l.multiWriter,
+		}
+	})
+}
+
+func (l *zapLogger) Output(w slago.Writer) slago.SlaLogger {
+	multiWriter := slago.NewMultiWriter()
+	multiWriter.AddWriter(w)
+	return &zapLogger{
+		logger:      newZap(multiWriter, l.atomicLevel).With(l.fields...),
+		atomicLevel: l.atomicLevel,
+		fields:      l.fields,
+		multiWriter: multiWriter,
+	}
+}
+
+func (l *zapLogger) Ctx(ctx context.Context) slago.SlaLogger {
+	return l.With().Ctx(ctx).Logger()
 }
 
 func (l *zapLogger) Trace() slago.Record {
@@ -88,36 +136,106 @@ func (l *zapLogger) Trace() slago.Record {
 }
 
 func (l *zapLogger) Debug() slago.Record {
-	return newZapRecord(zapcore.DebugLevel)
+	return newZapRecord(l.logger, zapcore.DebugLevel)
 }
 
 func (l *zapLogger) Info() slago.Record {
-	return newZapRecord(zapcore.InfoLevel)
+	return newZapRecord(l.logger, zapcore.InfoLevel)
+}
+
+func (l *zapLogger) Notice() slago.Record {
+	return l.Level(slago.NoticeLevel)
 }
 
 func (l *zapLogger) Warn() slago.Record {
-	return newZapRecord(zapcore.WarnLevel)
+	return newZapRecord(l.logger, zapcore.WarnLevel)
 }
 
 func (l *zapLogger) Error() slago.Record {
-	return newZapRecord(zapcore.ErrorLevel)
+	return newZapRecord(l.logger, zapcore.ErrorLevel)
 }
 
 func (l *zapLogger) Fatal() slago.Record {
-	return newZapRecord(zapcore.FatalLevel)
+	return newZapRecord(l.logger, zapcore.FatalLevel)
 }
 
 func (l *zapLogger) Panic() slago.Record {
-	return newZapRecord(zapcore.PanicLevel)
+	return newZapRecord(l.logger, zapcore.PanicLevel)
+}
+
+func (l *zapLogger) Level(lvl slago.Level) slago.Record {
+	return newZapRecord(l.logger, zapLevel(lvl))
 }
 
 func (l *zapLogger) WriteRaw(p []byte) {
 	_, err := l.multiWriter.Write(p)
 	if err != nil {
-		l.Error().Err(err).Msg("write raw error")
+		// report to status manager instead of logging into the failed writer
+		slago.DefaultStatusManager().Error("zap logger", "write raw error: %v", err)
 	}
 }
 
 func rf3339Encoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString(t.Format(slago.TimestampFormat))
 }
+
+func capitalLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if custom, ok := customLevel(lvl); ok {
+		enc.AppendString(custom.String())
+		return
+	}
+
+	zapcore.CapitalLevelEncoder(lvl, enc)
+}
+
+// zapLevel gets the zap level of slago level, custom level will be mapped
+// to an unused zap level when it's first used.
+func zapLevel(lvl slago.Level) zapcore.Level {
+	std := slago.StandardLevel(lvl)
+	if std == lvl {
+		return slagoLvlToZapLvl[std]
+	}
+
+	customLocker.RLock()
+	zl, ok := slagoLvlToCustomLvl[lvl]
+	customLocker.RUnlock()
+	if ok {
+		return zl
+	}
+
+	customLocker.Lock()
+	defer customLocker.Unlock()
+	if zl, ok := slagoLvlToCustomLvl[lvl]; ok {
+		return zl
+	}
+	zl = zapcore.DebugLevel - 1 - zapcore.Level(len(slagoLvlToCustomLvl))
+	if zl >= zapcore.DebugLevel {
+		// no unused zap level left, use the standard level instead
+		return slagoLvlToZapLvl[std]
+	}
+	slagoLvlToCustomLvl[lvl] = zl
+	customLvlToSlagoLvl[zl] = lvl
+
+	return zl
+}
+
+// customLevel gets the custom slago level which the zap level is mapped to.
+func customLevel(lvl zapcore.Level) (slago.Level, bool) {
+	if lvl >= zapcore.DebugLevel {
+		return 0, false
+	}
+
+	customLocker.RLock()
+	defer customLocker.RUnlock()
+	custom, ok := customLvlToSlagoLvl[lvl]
+	return custom, ok
+}
+
+// standardLevel gets the builtin zap level of the given zap level.
+func standardLevel(lvl zapcore.Level) zapcore.Level {
+	if custom, ok := customLevel(lvl); ok {
+		return slagoLvlToZapLvl[slago.StandardLevel(custom)]
+	}
+
+	return lvl
+}
@@ -15,8 +15,13 @@
 package slazap
 
 import (
+	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
 	"sync"
 	"time"
 
@@ -36,193 +41,205 @@ var (
 type zapRecord struct {
 	logger *zap.Logger
 	level  zapcore.Level
+	fields []zapcore.Field
+	time   time.Time
 }
 
-func newZapRecord(lvl zapcore.Level) *zapRecord {
+func newZapRecord(logger *zap.Logger, lvl zapcore.Level) *zapRecord {
 	r := recordPool.Get().(*zapRecord)
-	r.logger = zap.L()
+	r.logger = logger
 	r.level = lvl
+	r.fields = r.fields[:0]
+	r.time = time.Time{}
 
 	return r
 }
 
 func (r *zapRecord) Str(key, val string) slago.Record {
-	r.logger = r.logger.With(zap.String(key, val))
+	r.fields = append(r.fields, zap.String(key, val))
 	return r
 }
 
 func (r *zapRecord) Strs(key string, val []string) slago.Record {
-	r.logger = r.logger.With(zap.Strings(key, val))
+	r.fields = append(r.fields, zap.Strings(key, val))
 	return r
 }
 
 func (r *zapRecord) Bytes(key string, val []byte) slago.Record {
-	r.logger = r.logger.With(zap.ByteString(key, val))
+	r.fields = append(r.fields, zap.ByteString(key, val))
 	return r
 }
 
 func (r *zapRecord) Hex(key string, val []byte) slago.Record {
-	r.logger = r.logger.With(zap.String(key, hex.EncodeToString(val)))
+	r.fields = append(r.fields, zap.String(key, hex.EncodeToString(val)))
 	return r
 }
 
 func (r *zapRecord) Err(err error) slago.Record {
-	r.logger = r.logger.With(zap.Error(err))
-	return r
+	if err == nil {
+		return r
+	}
+
+	r.Object(slago.ErrorFieldKey, slago.ErrorObject(err))
+	return slago.AppendErrorStack(r, err)
+}
+
+func (r *zapRecord) Stack() slago.Record {
+	return r.Strs(slago.StackFieldKey, slago.Stack(0))
 }
 
 func (r *zapRecord) Errs(key string, errs []error) slago.Record {
-	r.logger = r.logger.With(zap.Errors(key, errs))
+	r.fields = append(r.fields, zap.Errors(key, errs))
 	return r
 }
 
 func (r *zapRecord) Bool(key string, b bool) slago.Record {
-	r.logger = r.logger.With(zap.Bool(key, b))
+	r.fields = append(r.fields, zap.Bool(key, b))
 	return r
 }
 
 func (r *zapRecord) Bools(key string, b []bool) slago.Record {
-	r.logger = r.logger.With(zap.Bools(key, b))
+	r.fields = append(r.fields, zap.Bools(key, b))
 	return r
 }
 
 func (r *zapRecord) Int(key string, val int) slago.Record {
-	r.logger = r.logger.With(zap.Int(key, val))
+	r.fields = append(r.fields, zap.Int(key, val))
 	return r
 }
 
 func (r *zapRecord) Ints(key string, val []int) slago.Record {
-	r.logger = r.logger.With(zap.Ints(key, val))
+	r.fields = append(r.fields, zap.Ints(key, val))
 	return r
 }
 
 func (r *zapRecord) Int8(key string, val int8) slago.Record {
-	r.logger = r.logger.With(zap.Int8(key, val))
+	r.fields = append(r.fields, zap.Int8(key, val))
 	return r
 }
 
 func (r *zapRecord) Ints8(key string, val []int8) slago.Record {
-	r.logger = r.logger.With(zap.Int8s(key, val))
+	r.fields = append(r.fields, zap.Int8s(key, val))
 	return r
 }
 
 func (r *zapRecord) Int16(key string, val int16) slago.Record {
-	r.logger = r.logger.With(zap.Int16(key, val))
+	r.fields = append(r.fields, zap.Int16(key, val))
 	return r
 }
 
 func (r *zapRecord) Ints16(key string, val []int16) slago.Record {
-	r.logger = r.logger.With(zap.Int16s(key, val))
+	r.fields = append(r.fields, zap.Int16s(key, val))
 	return r
 }
 
 func (r *zapRecord) Int32(key string, val int32) slago.Record {
-	r.logger = r.logger.With(zap.Int32(key, val))
+	r.fields = append(r.fields, zap.Int32(key, val))
 	return r
 }
 
 func (r *zapRecord) Ints32(key string, val []int32) slago.Record {
-	r.logger = r.logger.With(zap.Int32s(key, val))
+	r.fields = append(r.fields, zap.Int32s(key, val))
 	return r
 }
 
 func (r *zapRecord) Int64(key string, val int64) slago.Record {
-	r.logger = r.logger.With(zap.Int64(key, val))
+	r.fields = append(r.fields, zap.Int64(key, val))
 	return r
 }
 
 func (r *zapRecord) Ints64(key string, val []int64) slago.Record {
-	r.logger = r.logger.With(zap.Int64s(key, val))
+	r.fields = append(r.fields, zap.Int64s(key, val))
 	return r
 }
 
 func (r *zapRecord) Uint(key string, val uint) slago.Record {
-	r.logger.With(zap.Uint(key, val))
+	r.fields = append(r.fields, zap.Uint(key, val))
 	return r
 }
 
 func (r *zapRecord) Uints(key string, val []uint) slago.Record {
-	r.logger = r.logger.With(zap.Uints(key, val))
+	r.fields = append(r.fields, zap.Uints(key, val))
 	return r
 }
 
 func (r *zapRecord) Uint8(key string, val uint8) slago.Record {
-	r.logger = r.logger.With(zap.Uint8(key, val))
+	r.fields = append(r.fields, zap.Uint8(key, val))
 	return r
 }
 
 func (r *zapRecord) Uints8(key string, val []uint8) slago.Record {
-	r.logger = r.logger.With(zap.Uint8s(key, val))
+	r.fields = append(r.fields, zap.Uint8s(key, val))
 	return r
 }
 
 func (r *zapRecord) Uint16(key string, val uint16) slago.Record {
-	r.logger = r.logger.With(zap.Uint16(key, val))
+	r.fields = append(r.fields, zap.Uint16(key, val))
 	return r
 }
 
 func (r *zapRecord) Uints16(key string, val []uint16) slago.Record {
-	r.logger = r.logger.With(zap.Uint16s(key, val))
+	r.fields = append(r.fields, zap.Uint16s(key, val))
 	return r
 }
 
 func (r *zapRecord) Uint32(key string, val uint32) slago.Record {
-	r.logger = r.logger.With(zap.Uint32(key, val))
+	r.fields = append(r.fields, zap.Uint32(key, val))
 	return r
 }
 
 func (r *zapRecord) Uints32(key string, val []uint32) slago.Record {
-	r.logger = r.logger.With(zap.Uint32s(key, val))
+	r.fields = append(r.fields, zap.Uint32s(key, val))
 	return r
 }
 
 func (r *zapRecord) Uint64(key string, val uint64) slago.Record {
-	r.logger = r.logger.With(zap.Uint64(key, val))
+	r.fields = append(r.fields, zap.Uint64(key, val))
 	return r
 }
 
 func (r *zapRecord) Uints64(key string, val []uint64) slago.Record {
-	r.logger = r.logger.With(zap.Uint64s(key, val))
+	r.fields = append(r.fields, zap.Uint64s(key, val))
 	return r
 }
 
 func (r *zapRecord) Float32(key string, val float32) slago.Record {
-	r.logger = r.logger.With(zap.Float32(key, val))
+	r.fields = append(r.fields, zap.Float32(key, val))
 	return r
 }
 
 func (r *zapRecord) Floats32(key string, val []float32) slago.Record {
-	r.logger = r.logger.With(zap.Float32s(key, val))
+	r.fields = append(r.fields, zap.Float32s(key, val))
 	return r
 }
 
 func (r *zapRecord) Float64(key string, val float64) slago.Record {
-	r.logger = r.logger.With(zap.Float64(key, val))
+	r.fields = append(r.fields, zap.Float64(key, val))
 	return r
 }
 
 func (r *zapRecord) Floats64(key string, val []float64) slago.Record {
-	r.logger = r.logger.With(zap.Float64s(key, val))
+	r.fields = append(r.fields, zap.Float64s(key, val))
 	return r
 }
 
 func (r *zapRecord) Time(key string, val time.Time) slago.Record {
-	r.logger = r.logger.With(zap.Time(key, val))
+	r.fields = append(r.fields, zap.Time(key, val))
 	return r
 }
 
 func (r *zapRecord) Times(key string, val []time.Time) slago.Record {
-	r.logger = r.logger.With(zap.Times(key, val))
+	r.fields = append(r.fields, zap.Times(key, val))
 	return r
 }
 
 func (r *zapRecord) Dur(key string, val time.Duration) slago.Record {
-	r.logger = r.logger.With(zap.Duration(key, val))
+	r.fields = append(r.fields, zap.Duration(key, val))
 	return r
 }
 
 func (r *zapRecord) Durs(key string, val []time.Duration) slago.Record {
-	r.logger = r.logger.With(zap.Durations(key, val))
+	r.fields = append(r.fields, zap.Durations(key, val))
 	return r
 }
 
@@ -260,10 +277,115 @@ func (r *zapRecord) Interface(key string, val interface{}) slago.Record {
 		return r.Time(key, val.(time.Time))
 	case time.Duration:
 		return r.Dur(key, val.(time.Duration))
+	case slago.ObjectMarshaler:
+		return r.Object(key, val.(slago.ObjectMarshaler))
+	case slago.ArrayMarshaler:
+		return r.Array(key, val.(slago.ArrayMarshaler))
 	default:
-		r.logger = r.logger.With(zap.String(key, fmt.Sprint(val)))
+		r.fields = append(r.fields, zap.String(key, fmt.Sprint(val)))
+	}
+
+	return r
+}
+
+func (r *zapRecord) RawJSON(key string, val []byte) slago.Record {
+	r.fields = append(r.fields, zap.Reflect(key, json.RawMessage(val)))
+	return r
+}
+
+func (r *zapRecord) Stringer(key string, val fmt.Stringer) slago.Record {
+	if val == nil {
+		r.fields = append(r.fields, zap.Reflect(key, nil))
+		return r
+	}
+
+	r.fields = append(r.fields, zap.Stringer(key, val))
+	return r
+}
+
+func (r *zapRecord) IPAddr(key string, ip net.IP) slago.Record {
+	r.fields = append(r.fields, zap.Stringer(key, ip))
+	return r
+}
+
+func (r *zapRecord) IPPrefix(key string, pfx net.IPNet) slago.Record {
+	r.fields = append(r.fields, zap.Stringer(key, &pfx))
+	return r
+}
+
+func (r *zapRecord) MACAddr(key string, ha net.HardwareAddr) slago.Record {
+	r.fields = append(r.fields, zap.Stringer(key, ha))
+	return r
+}
+
+func (r *zapRecord) URL(key string, val *url.URL) slago.Record {
+	if val == nil {
+		r.fields = append(r.fields, zap.Reflect(key, nil))
+		return r
+	}
+
+	r.fields = append(r.fields, zap.Stringer(key, val))
+	return r
+}
+
+func (r *zapRecord) Dict(key string, f func(r slago.Record)) slago.Record {
+	d := &zapRecord{}
+	f(d)
+	r.fields = append(r.fields, zap.Object(key, fieldsMarshaler(d.fields)))
+	return r
+}
+
+func (r *zapRecord) Object(key string, obj slago.ObjectMarshaler) slago.Record {
+	r.fields = append(r.fields, zap.Object(key, &objectMarshaler{obj}))
+	return r
+}
+
+func (r *zapRecord) Array(key string, arr slago.ArrayMarshaler) slago.Record {
+	r.fields = append(r.fields, zap.Array(key, &arrayMarshaler{arr}))
+	return r
+}
+
+func (r *zapRecord) Any(key string, val interface{}) slago.Record {
+	return slago.AppendAny(r, key, val)
+}
+
+func (r *zapRecord) Fields(fields map[string]interface{}) slago.Record {
+	return slago.AppendFields(r, fields)
+}
+
+func (r *zapRecord) Marker(names ...string) slago.Record {
+	if len(names) == 0 {
+		return r
+	}
+
+	return r.Strs(slago.MarkerFieldKey, names)
+}
+
+func (r *zapRecord) Ctx(ctx context.Context) slago.Record {
+	return slago.ExtractContext(ctx, r)
+}
+
+func (r *zapRecord) Func(key string, f func() interface{}) slago.Record {
+	if r.Enabled() {
+		r.Any(key, f())
 	}
+	return r
+}
 
+func (r *zapRecord) Lazy(f func(r slago.Record)) slago.Record {
+	if r.Enabled() {
+		f(r)
+	}
+	return r
+}
+
+func (r *zapRecord) Enabled() bool {
+	// nested record is always enabled
+	return r.logger == nil || r.logger.Core().Enabled(r.level)
+}
+
+func (r *zapRecord) Timestamp(t time.Time) slago.Record {
+	r.time = t
 	return r
 }
 
@@ -273,41 +395,139 @@ func (r *zapRecord) Msg(originMsg ...string) {
 		msg = originMsg[0]
 	}
 
-	switch r.level {
-	case zapcore.DebugLevel:
-		r.logger.Debug(msg)
-	case zapcore.InfoLevel:
-		r.logger.Info(msg)
-	case zapcore.WarnLevel:
-		r.logger.Warn(msg)
-	case zapcore.ErrorLevel:
-		r.logger.Error(msg)
-	case zapcore.FatalLevel:
-		r.logger.Fatal(msg)
-	case zapcore.PanicLevel:
-		r.logger.Panic(msg)
+	r.write(msg)
+}
+
+func (r *zapRecord) Msgf(format string, v ...interface{}) {
+	r.write(fmt.Sprintf(format, v...))
+}
+
+func (r *zapRecord) Msgt(template string, args ...interface{}) {
+	r.Msg(slago.AppendTemplate(r, template, args...))
+}
+
+func (r *zapRecord) write(msg string) {
+	// nested record has no logger, just ignore it
+	if r.logger == nil {
+		return
+	}
+
+	slago.AppendCaller(r)
+
+	switch standardLevel(r.level) {
+	case zapcore.FatalLevel, zapcore.PanicLevel:
+		r.terminate(msg)
+		return
+	}
+
+	if ce := r.logger.Check(r.level, msg); ce != nil {
+		if !r.time.IsZero() {
+			ce.Time = r.time
+		}
+		ce.Write(r.fields...)
 	}
 
 	recordPool.Put(r)
 }
 
-func (r *zapRecord) Msgf(format string, v ...interface{}) {
-	sl := r.logger.Sugar()
-
-	switch r.level {
-	case zapcore.DebugLevel:
-		sl.Debugf(format, v...)
-	case zapcore.InfoLevel:
-		sl.Infof(format, v...)
-	case zapcore.WarnLevel:
-		sl.Warnf(format, v...)
-	case zapcore.ErrorLevel:
-		sl.Errorf(format, v...)
-	case zapcore.FatalLevel:
-		sl.Fatalf(format, v...)
-	case zapcore.PanicLevel:
-		sl.Panicf(format, v...)
+// terminate writes fatal or panic event via core directly, then shuts down
+// slago before the process exits or panics.
+func (r *zapRecord) terminate(msg string) {
+	ent := zapcore.Entry{Level: r.level, Time: r.time, Message: msg}
+	if ent.Time.IsZero() {
+		ent.Time = time.Now()
+	}
+	if ce := r.logger.Core().Check(ent, nil); ce != nil {
+		ce.Write(r.fields...)
 	}
-
 	recordPool.Put(r)
+
+	slago.ShutdownBeforeExit()
+	if standardLevel(ent.Level) == zapcore.FatalLevel {
+		os.Exit(1)
+	}
+	panic(msg)
+}
+
+// fieldsMarshaler encodes zap fields as a nested object.
+type fieldsMarshaler []zapcore.Field
+
+func (fs fieldsMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range fs {
+		f.AddTo(enc)
+	}
+
+	return nil
+}
+
+// objectMarshaler adapts slago.ObjectMarshaler to zapcore.ObjectMarshaler.
+type objectMarshaler struct {
+	obj slago.ObjectMarshaler
+}
+
+func (m *objectMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	r := &zapRecord{}
+	if err := m.obj.MarshalSlagoObject(r); err != nil {
+		return err
+	}
+
+	return fieldsMarshaler(r.fields).MarshalLogObject(enc)
+}
+
+// arrayMarshaler adapts slago.ArrayMarshaler to zapcore.ArrayMarshaler.
+type arrayMarshaler struct {
+	arr slago.ArrayMarshaler
+}
+
+func (m *arrayMarshaler) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return m.arr.MarshalSlagoArray(&arrayEncoder{enc})
+}
+
+// arrayEncoder adapts zapcore.ArrayEncoder to slago.ArrayEncoder.
+type arrayEncoder struct {
+	enc zapcore.ArrayEncoder
+}
+
+func (e *arrayEncoder) AppendStr(val string) {
+	e.enc.AppendString(val)
+}
+
+func (e *arrayEncoder) AppendBool(val bool) {
+	e.enc.AppendBool(val)
+}
+
+func (e *arrayEncoder) AppendInt(val int) {
+	e.enc.AppendInt(val)
+}
+
+func (e *arrayEncoder) AppendInt64(val int64) {
+	e.enc.AppendInt64(val)
+}
+
+func (e *arrayEncoder) AppendUint(val uint) {
+	e.enc.AppendUint(val)
+}
+
+func (e *arrayEncoder) AppendUint64(val uint64) {
+	e.enc.AppendUint64(val)
+}
+
+func (e *arrayEncoder) AppendFloat64(val float64) {
+	e.enc.AppendFloat64(val)
+}
+
+func (e *arrayEncoder) AppendTime(val time.Time) {
+	e.enc.AppendTime(val)
+}
+
+func (e *arrayEncoder) AppendDur(val time.Duration) {
+	e.enc.AppendDuration(val)
+}
+
+func (e *arrayEncoder) AppendObject(obj slago.ObjectMarshaler) {
+	_ = e.enc.AppendObject(&objectMarshaler{obj})
+}
+
+func (e *arrayEncoder) AppendInterface(val interface{}) {
+	_ = e.enc.AppendReflected(val)
 }
@@ -0,0 +1,342 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slazero
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/coolerfall/slago"
+	"github.com/rs/zerolog"
+)
+
+// contextRecord is a record which holds fields in zerolog context,
+// it's used to create child logger with fields.
+type contextRecord struct {
+	ctx zerolog.Context
+}
+
+func newContextRecord(ctx zerolog.Context) *contextRecord {
+	return &contextRecord{
+		ctx: ctx,
+	}
+}
+
+func (r *contextRecord) Str(key, val string) slago.Record {
+	r.ctx = r.ctx.Str(key, val)
+	return r
+}
+
+func (r *contextRecord) Strs(key string, val []string) slago.Record {
+	r.ctx = r.ctx.Strs(key, val)
+	return r
+}
+
+func (r *contextRecord) Bytes(key string, val []byte) slago.Record {
+	r.ctx = r.ctx.Bytes(key, val)
+	return r
+}
+
+func (r *contextRecord) Hex(key string, val []byte) slago.Record {
+	r.ctx = r.ctx.Hex(key, val)
+	return r
+}
+
+func (r *contextRecord) Err(err error) slago.Record {
+	if err == nil {
+		return r
+	}
+
+	r.Object(slago.ErrorFieldKey, slago.ErrorObject(err))
+	return slago.AppendErrorStack(r, err)
+}
+
+func (r *contextRecord) Stack() slago.Record {
+	return r.Strs(slago.StackFieldKey, slago.Stack(0))
+}
+
+func (r *contextRecord) Errs(key string, errs []error) slago.Record {
+	r.ctx = r.ctx.Errs(key, errs)
+	return r
+}
+
+func (r *contextRecord) Bool(key string, val bool) slago.Record {
+	r.ctx = r.ctx.Bool(key, val)
+	return r
+}
+
+func (r *contextRecord) Bools(key string, val []bool) slago.Record {
+	r.ctx = r.ctx.Bools(key, val)
+	return r
+}
+
+func (r *contextRecord) Int(key string, val int) slago.Record {
+	r.ctx = r.ctx.Int(key, val)
+	return r
+}
+
+func (r *contextRecord) Ints(key string, val []int) slago.Record {
+	r.ctx = r.ctx.Ints(key, val)
+	return r
+}
+
+func (r *contextRecord) Int8(key string, val int8) slago.Record {
+	r.ctx = r.ctx.Int8(key, val)
+	return r
+}
+
+func (r *contextRecord) Ints8(key string, val []int8) slago.Record {
+	r.ctx = r.ctx.Ints8(key, val)
+	return r
+}
+
+func (r *contextRecord) Int16(key string, val int16) slago.Record {
+	r.ctx = r.ctx.Int16(key, val)
+	return r
+}
+
+func (r *contextRecord) Ints16(key string, val []int16) slago.Record {
+	r.ctx = r.ctx.Ints16(key, val)
+	return r
+}
+
+func (r *contextRecord) Int32(key string, val int32) slago.Record {
+	r.ctx = r.ctx.Int32(key, val)
+	return r
+}
+
+func (r *contextRecord) Ints32(key string, val []int32) slago.Record {
+	r.ctx = r.ctx.Ints32(key, val)
+	return r
+}
+
+func (r *contextRecord) Int64(key string, val int64) slago.Record {
+	r.ctx = r.ctx.Int64(key, val)
+	return r
+}
+
+func (r *contextRecord) Ints64(key string, val []int64) slago.Record {
+	r.ctx = r.ctx.Ints64(key, val)
+	return r
+}
+
+func (r *contextRecord) Uint(key string, val uint) slago.Record {
+	r.ctx = r.ctx.Uint(key, val)
+	return r
+}
+
+func (r *contextRecord) Uints(key string, val []uint) slago.Record {
+	r.ctx = r.ctx.Uints(key, val)
+	return r
+}
+
+func (r *contextRecord) Uint8(key string, val uint8) slago.Record {
+	r.ctx = r.ctx.Uint8(key, val)
+	return r
+}
+
+func (r *contextRecord) Uints8(key string, val []uint8) slago.Record {
+	r.ctx = r.ctx.Uints8(key, val)
+	return r
+}
+
+func (r *contextRecord) Uint16(key string, val uint16) slago.Record {
+	r.ctx = r.ctx.Uint16(key, val)
+	return r
+}
+
+func (r *contextRecord) Uints16(key string, val []uint16) slago.Record {
+	r.ctx = r.ctx.Uints16(key, val)
+	return r
+}
+
+func (r *contextRecord) Uint32(key string, val uint32) slago.Record {
+	r.ctx = r.ctx.Uint32(key, val)
+	return r
+}
+
+func (r *contextRecord) Uints32(key string, val []uint32) slago.Record {
+	r.ctx = r.ctx.Uints32(key, val)
+	return r
+}
+
+func (r *contextRecord) Uint64(key string, val uint64) slago.Record {
+	r.ctx = r.ctx.Uint64(key, val)
+	return r
+}
+
+func (r *contextRecord) Uints64(key string, val []uint64) slago.Record {
+	r.ctx = r.ctx.Uints64(key, val)
+	return r
+}
+
+func (r *contextRecord) Float32(key string, val float32) slago.Record {
+	r.ctx = r.ctx.Float32(key, val)
+	return r
+}
+
+func (r *contextRecord) Floats32(key string, val []float32) slago.Record {
+	r.ctx = r.ctx.Floats32(key, val)
+	return r
+}
+
+func (r *contextRecord) Float64(key string, val float64) slago.Record {
+	r.ctx = r.ctx.Float64(key, val)
+	return r
+}
+
+func (r *contextRecord) Floats64(key string, val []float64) slago.Record {
+	r.ctx = r.ctx.Floats64(key, val)
+	return r
+}
+
+func (r *contextRecord) Time(key string, val time.Time) slago.Record {
+	r.ctx = r.ctx.Time(key, val)
+	return r
+}
+
+func (r *contextRecord) Times(key string, val []time.Time) slago.Record {
+	r.ctx = r.ctx.Times(key, val)
+	return r
+}
+
+func (r *contextRecord) Dur(key string, val time.Duration) slago.Record {
+	r.ctx = r.ctx.Dur(key, val)
+	return r
+}
+
+func (r *contextRecord) Durs(key string, val []time.Duration) slago.Record {
+	r.ctx = r.ctx.Durs(key, val)
+	return r
+}
+
+func (r *contextRecord) RawJSON(key string, val []byte) slago.Record {
+	r.ctx = r.ctx.RawJSON(key, val)
+	return r
+}
+
+func (r *contextRecord) Stringer(key string, val fmt.Stringer) slago.Record {
+	r.ctx = r.ctx.Stringer(key, val)
+	return r
+}
+
+func (r *contextRecord) IPAddr(key string, ip net.IP) slago.Record {
+	r.ctx = r.ctx.IPAddr(key, ip)
+	return r
+}
+
+func (r *contextRecord) IPPrefix(key string, pfx net.IPNet) slago.Record {
+	r.ctx = r.ctx.IPPrefix(key, pfx)
+	return r
+}
+
+func (r *contextRecord) MACAddr(key string, ha net.HardwareAddr) slago.Record {
+	r.ctx = r.ctx.MACAddr(key, ha)
+	return r
+}
+
+func (r *contextRecord) URL(key string, val *url.URL) slago.Record {
+	if val == nil {
+		r.ctx = r.ctx.Interface(key, nil)
+		return r
+	}
+
+	r.ctx = r.ctx.Stringer(key, val)
+	return r
+}
+
+func (r *contextRecord) Dict(key string, f func(r slago.Record)) slago.Record {
+	d := zerolog.Dict()
+	f(newNestedRecord(d))
+	r.ctx = r.ctx.Dict(key, d)
+	return r
+}
+
+func (r *contextRecord) Object(key string, obj slago.ObjectMarshaler) slago.Record {
+	r.ctx = r.ctx.Object(key, &objectMarshaler{obj})
+	return r
+}
+
+func (r *contextRecord) Array(key string, arr slago.ArrayMarshaler) slago.Record {
+	r.ctx = r.ctx.Array(key, newArray(arr))
+	return r
+}
+
+func (r *contextRecord) Interface(key string, val interface{}) slago.Record {
+	switch v := val.(type) {
+	case slago.ObjectMarshaler:
+		return r.Object(key, v)
+	case slago.ArrayMarshaler:
+		return r.Array(key, v)
+	}
+
+	r.ctx = r.ctx.Interface(key, val)
+	return r
+}
+
+func (r *contextRecord) Any(key string, val interface{}) slago.Record {
+	return slago.AppendAny(r, key, val)
+}
+
+func (r *contextRecord) Fields(fields map[string]interface{}) slago.Record {
+	return slago.AppendFields(r, fields)
+}
+
+func (r *contextRecord) Marker(names ...string) slago.Record {
+	if len(names) == 0 {
+		return r
+	}
+
+	return r.Strs(slago.MarkerFieldKey, names)
+}
+
+func (r *contextRecord) Ctx(ctx context.Context) slago.Record {
+	return slago.ExtractContext(ctx, r)
+}
+
+// Timestamp takes no effect since the time of bound fields is meaningless.
+func (r *contextRecord) Timestamp(_ time.Time) slago.Record {
+	return r
+}
+
+func (r *contextRecord) Func(key string, f func() interface{}) slago.Record {
+	if r.Enabled() {
+		r.Any(key, f())
+	}
+	return r
+}
+
+func (r *contextRecord) Lazy(f func(r slago.Record)) slago.Record {
+	if r.Enabled() {
+		f(r)
+	}
+	return r
+}
+
+func (r *contextRecord) Enabled() bool {
+	return true
+}
+
+func (r *contextRecord) Msg(_ ...string) {
+}
+
+func (r *contextRecord) Msgf(_ string, _ ...interface{}) {
+}
+
+func (r *contextRecord) Msgt(_ string, _ ...interface{}) {
+}
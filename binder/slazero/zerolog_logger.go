@@ -15,9 +15,11 @@
 package slazero
 
 import (
+	"context"
+	"sync/atomic"
+
 	"github.com/coolerfall/slago"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
 var (
@@ -35,24 +37,27 @@ var (
 // zeroLogger is an implementation of SlaLogger.
 type zeroLogger struct {
 	logger      zerolog.Logger
+	level       *int32
 	multiWriter *slago.MultiWriter
 }
 
 // NewZeroLogger creates a new instance of zeroLogger used to be bound to slago.
+// The level is kept in this logger, and the global level and logger of zerolog
+// won't be changed. Only the field names of zerolog are set since they can't be
+// set in logger.
 func NewZeroLogger() slago.SlaLogger {
-	zerolog.SetGlobalLevel(zerolog.TraceLevel)
 	zerolog.TimeFieldFormat = slago.TimestampFormat
 	zerolog.LevelFieldName = slago.LevelFieldKey
 	zerolog.TimestampFieldName = slago.TimestampFieldKey
 	zerolog.MessageFieldName = slago.MessageFieldKey
-	zerolog.LevelFieldMarshalFunc = capitalLevel
 
 	multiWriter := slago.NewMultiWriter()
-	logger := zerolog.New(multiWriter).With().Timestamp().Logger()
-	log.Logger = logger
+	level := int32(zerolog.TraceLevel)
 
+	// the timestamp will be added by record, so the event time can be overridden
 	return &zeroLogger{
-		logger:      logger,
+		logger:      zerolog.New(multiWriter),
+		level:       &level,
 		multiWriter: multiWriter,
 	}
 }
@@ -70,41 +75,95 @@ func (l *zeroLogger) ResetWriter() {
 }
 
 func (l *zeroLogger) SetLevel(lvl slago.Level) {
-	zerolog.SetGlobalLevel(slagoLvlToZeroLvl[lvl])
+	if lvl >= slago.OffLevel {
+		atomic.StoreInt32(l.level, int32(zerolog.Disabled))
+		return
+	}
+
+	atomic.StoreInt32(l.level, int32(slagoLvlToZeroLvl[slago.StandardLevel(lvl)]))
+}
+
+func (l *zeroLogger) With() *slago.FieldContext {
+	r := newContextRecord(l.logger.With())
+	return slago.NewFieldContext(r, func() slago.SlaLogger {
+		return &zeroLogger{
+			logger:      r.ctx.Logger(),
+			level:       l.level,
+			multiWriter: l.multiWriter,
+		}
+	})
+}
+
+func (l *zeroLogger) Output(w slago.Writer) slago.SlaLogger {
+	multiWriter := slago.NewMultiWriter()
+	multiWriter.AddWriter(w)
+	return &zeroLogger{
+		logger:      l.logger.Output(multiWriter),
+		level:       l.level,
+		multiWriter: multiWriter,
+	}
+}
+
+func (l *zeroLogger) Ctx(ctx context.Context) slago.SlaLogger {
+	return l.With().Ctx(ctx).Logger()
 }
 
 func (l *zeroLogger) Trace() slago.Record {
-	return newZeroRecord(l.logger.Trace())
+	return newZeroRecord(l.event(zerolog.TraceLevel))
 }
 
 func (l *zeroLogger) Debug() slago.Record {
-	return newZeroRecord(l.logger.Debug())
+	return newZeroRecord(l.event(zerolog.DebugLevel))
 }
 
 func (l *zeroLogger) Info() slago.Record {
-	return newZeroRecord(l.logger.Info())
+	return newZeroRecord(l.event(zerolog.InfoLevel))
+}
+
+func (l *zeroLogger) Notice() slago.Record {
+	return l.Level(slago.NoticeLevel)
 }
 
 func (l *zeroLogger) Warn() slago.Record {
-	return newZeroRecord(l.logger.Warn())
+	return newZeroRecord(l.event(zerolog.WarnLevel))
 }
 
 func (l *zeroLogger) Error() slago.Record {
-	return newZeroRecord(l.logger.Error())
+	return newZeroRecord(l.event(zerolog.ErrorLevel))
 }
 
 func (l *zeroLogger) Fatal() slago.Record {
-	return newZeroRecord(l.logger.Fatal())
+	return newTerminalRecord(l.event(zerolog.FatalLevel), exitAfterShutdown)
 }
 
 func (l *zeroLogger) Panic() slago.Record {
-	return newZeroRecord(l.logger.Panic())
+	return newTerminalRecord(l.event(zerolog.PanicLevel), panicAfterShutdown)
+}
+
+func (l *zeroLogger) Level(lvl slago.Level) slago.Record {
+	std := slago.StandardLevel(lvl)
+	var e *zerolog.Event
+	if std == lvl {
+		e = l.event(slagoLvlToZeroLvl[std])
+	} else {
+		e = l.customEvent(lvl)
+	}
+
+	switch std {
+	case slago.FatalLevel:
+		return newTerminalRecord(e, exitAfterShutdown)
+	case slago.PanicLevel:
+		return newTerminalRecord(e, panicAfterShutdown)
+	default:
+		return newZeroRecord(e)
+	}
 }
 
 func (l *zeroLogger) WriteRaw(p []byte) {
 	_, err := l.multiWriter.Write(p)
 	if err != nil {
-		l.Error().Err(err).Msg("write raw error")
+		// report to status manager instead of logging into the failed writer
+		slago.DefaultStatusManager().Error("zerolog logger", "write raw error: %v", err)
 	}
 }
 
@@ -130,3 +189,29 @@ func capitalLevel(l zerolog.Level) string {
 		return "TRACE"
 	}
 }
+
+// customEvent creates an event with the name of custom level. Zerolog panics
+// with unknown level, so the event is created without level and the custom
+// level name is added only once.
+func (l *zeroLogger) customEvent(lvl slago.Level) *zerolog.Event {
+	if !l.enabled(slagoLvlToZeroLvl[slago.StandardLevel(lvl)]) {
+		return nil
+	}
+
+	return l.logger.Log().Str(zerolog.LevelFieldName, lvl.String())
+}
+
+// event creates an event with the given level, nil will be returned if the
+// level is lower than the level of this logger. The event is created without
+// level like custom level, so it won't be filtered by the global level of zerolog.
+func (l *zeroLogger) event(zl zerolog.Level) *zerolog.Event {
+	if !l.enabled(zl) {
+		return nil
+	}
+
+	return l.logger.Log().Str(zerolog.LevelFieldName, capitalLevel(zl))
+}
+
+func (l *zeroLogger) enabled(zl zerolog.Level) bool {
+	return zl >= zerolog.Level(atomic.LoadInt32(l.level))
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slazero
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/coolerfall/slago"
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+func TestZeroLogger(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "zerolog logger test")
+}
+
+// register only once since all the specs are run in every test
+var auditLevelErr = slago.RegisterLevel(35, "AUDIT", "magenta")
+
+var _ = Describe("zerolog logger", func() {
+	var lc *slago.LoggerContext
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		Expect(auditLevelErr).To(BeNil())
+		lc = slago.NewLoggerContext()
+		Expect(lc.Bind(NewZeroLogger())).To(BeNil())
+		buf = new(bytes.Buffer)
+		lc.Logger().AddWriter(&bufferWriter{buf})
+	})
+	AfterEach(func() {
+		lc.Reset()
+	})
+
+	table.DescribeTable("record",
+		func(log func(l slago.SlaLogger), expected ...string) {
+			log(lc.Logger("acme"))
+			for _, e := range expected {
+				Expect(buf.String()).To(ContainSubstring(e))
+			}
+		},
+		table.Entry("nil stringer", func(l slago.SlaLogger) {
+			l.Info().Stringer("nil_stringer", nil).Stringer("dur", time.Second).Msg("")
+		}, `"nil_stringer":null`, `"dur":"1s"`),
+		table.Entry("custom level", func(l slago.SlaLogger) {
+			l.Level(35).Msg("audit")
+		}, `"level":"AUDIT"`, `"message":"audit"`),
+		table.Entry("notice level", func(l slago.SlaLogger) {
+			l.Notice().Msg("notice")
+		}, `"level":"NOTICE"`),
+		table.Entry("timestamp", func(l slago.SlaLogger) {
+			l.Info().Timestamp(time.Date(2021, 1, 2, 3, 4, 5, 6, time.FixedZone("", 8*3600))).Msg("")
+		}, `"time":"2021-01-02T03:04:05.000000006+08:00"`),
+		table.Entry("with fields", func(l slago.SlaLogger) {
+			l.With().Str("str", "value").Logger().Warn().Msg("with")
+		}, `"str":"value"`, `"level":"WARN"`, `"logger_name":"acme"`),
+	)
+
+	It("isolated in contexts", func() {
+		globalLevel := zerolog.GlobalLevel()
+		lc1, lc2 := slago.NewLoggerContext(), slago.NewLoggerContext()
+		binder1, binder2 := NewZeroLogger(), NewZeroLogger()
+		Expect(lc1.Bind(binder1)).To(BeNil())
+		Expect(lc2.Bind(binder2)).To(BeNil())
+		Expect(lc2.Bind(binder1)).NotTo(BeNil())
+		buf1, buf2 := new(bytes.Buffer), new(bytes.Buffer)
+		lc1.Logger().AddWriter(&bufferWriter{buf1})
+		lc2.Logger().AddWriter(&bufferWriter{buf2})
+
+		binder1.SetLevel(slago.ErrorLevel)
+		lc1.Logger().Info().Msg("one")
+		lc2.Logger().Trace().Msg("two")
+		Expect(buf1.Len()).To(BeZero())
+		Expect(buf2.String()).To(ContainSubstring(`"level":"TRACE"`))
+		Expect(buf2.String()).To(ContainSubstring(`"message":"two"`))
+		Expect(zerolog.GlobalLevel()).To(Equal(globalLevel))
+
+		lc1.Reset()
+		lc2.Reset()
+	})
+})
+
+type bufferWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *bufferWriter) Write(p []byte) (n int, err error) {
+	return w.buf.Write(p)
+}
+
+func (w *bufferWriter) Encoder() slago.Encoder {
+	return nil
+}
+
+func (w *bufferWriter) Filter() slago.Filter {
+	return nil
+}
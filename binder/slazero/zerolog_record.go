@@ -15,6 +15,11 @@
 package slazero
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
 	"sync"
 	"time"
 
@@ -32,11 +37,33 @@ var (
 
 type zeroRecord struct {
 	event *zerolog.Event
+	time  time.Time
+	done  func(msg string)
+	// nested record only collects fields of nested object, and never pooled
+	nested bool
 }
 
 func newZeroRecord(e *zerolog.Event) *zeroRecord {
 	r := recordPool.Get().(*zeroRecord)
 	r.event = e
+	r.time = time.Time{}
+	r.done = nil
+	r.nested = false
+	return r
+}
+
+// newNestedRecord creates a record to collect fields of nested object.
+func newNestedRecord(e *zerolog.Event) *zeroRecord {
+	return &zeroRecord{
+		event:  e,
+		nested: true,
+	}
+}
+
+// newTerminalRecord creates a record which calls done after the event is written.
+func newTerminalRecord(e *zerolog.Event, done func(msg string)) *zeroRecord {
+	r := newZeroRecord(e)
+	r.done = done
 	return r
 }
 
@@ -61,8 +88,16 @@ func (r *zeroRecord) Hex(key string, val []byte) slago.Record {
 }
 
 func (r *zeroRecord) Err(err error) slago.Record {
-	r.event.Err(err)
-	return r
+	if err == nil {
+		return r
+	}
+
+	r.Object(slago.ErrorFieldKey, slago.ErrorObject(err))
+	return slago.AppendErrorStack(r, err)
+}
+
+func (r *zeroRecord) Stack() slago.Record {
+	return r.Strs(slago.StackFieldKey, slago.Stack(0))
 }
 
 func (r *zeroRecord) Errs(key string, errs []error) slago.Record {
@@ -220,21 +255,248 @@ func (r *zeroRecord) Durs(key string, val []time.Duration) slago.Record {
 	return r
 }
 
+func (r *zeroRecord) RawJSON(key string, val []byte) slago.Record {
+	r.event.RawJSON(key, val)
+	return r
+}
+
+func (r *zeroRecord) Stringer(key string, val fmt.Stringer) slago.Record {
+	r.event.Stringer(key, val)
+	return r
+}
+
+func (r *zeroRecord) IPAddr(key string, ip net.IP) slago.Record {
+	r.event.IPAddr(key, ip)
+	return r
+}
+
+func (r *zeroRecord) IPPrefix(key string, pfx net.IPNet) slago.Record {
+	r.event.IPPrefix(key, pfx)
+	return r
+}
+
+func (r *zeroRecord) MACAddr(key string, ha net.HardwareAddr) slago.Record {
+	r.event.MACAddr(key, ha)
+	return r
+}
+
+func (r *zeroRecord) URL(key string, val *url.URL) slago.Record {
+	if val == nil {
+		r.event.Interface(key, nil)
+		return r
+	}
+
+	r.event.Stringer(key, val)
+	return r
+}
+
+func (r *zeroRecord) Dict(key string, f func(r slago.Record)) slago.Record {
+	d := zerolog.Dict()
+	f(newNestedRecord(d))
+	r.event.Dict(key, d)
+	return r
+}
+
+func (r *zeroRecord) Object(key string, obj slago.ObjectMarshaler) slago.Record {
+	r.event.Object(key, &objectMarshaler{obj})
+	return r
+}
+
+func (r *zeroRecord) Array(key string, arr slago.ArrayMarshaler) slago.Record {
+	r.event.Array(key, newArray(arr))
+	return r
+}
+
 func (r *zeroRecord) Interface(key string, val interface{}) slago.Record {
+	switch v := val.(type) {
+	case slago.ObjectMarshaler:
+		return r.Object(key, v)
+	case slago.ArrayMarshaler:
+		return r.Array(key, v)
+	}
+
 	r.event.Interface(key, val)
 	return r
 }
 
+func (r *zeroRecord) Any(key string, val interface{}) slago.Record {
+	return slago.AppendAny(r, key, val)
+}
+
+func (r *zeroRecord) Fields(fields map[string]interface{}) slago.Record {
+	return slago.AppendFields(r, fields)
+}
+
+func (r *zeroRecord) Marker(names ...string) slago.Record {
+	if len(names) == 0 {
+		return r
+	}
+
+	return r.Strs(slago.MarkerFieldKey, names)
+}
+
+func (r *zeroRecord) Ctx(ctx context.Context) slago.Record {
+	return slago.ExtractContext(ctx, r)
+}
+
+func (r *zeroRecord) Func(key string, f func() interface{}) slago.Record {
+	if r.Enabled() {
+		r.Any(key, f())
+	}
+	return r
+}
+
+func (r *zeroRecord) Lazy(f func(r slago.Record)) slago.Record {
+	if r.Enabled() {
+		f(r)
+	}
+	return r
+}
+
+func (r *zeroRecord) Enabled() bool {
+	return r.event.Enabled()
+}
+
+func (r *zeroRecord) Timestamp(t time.Time) slago.Record {
+	r.time = t
+	return r
+}
+
 func (r *zeroRecord) Msg(originMsg ...string) {
+	// nested record has no message, just ignore it
+	if r.nested {
+		return
+	}
+
+	r.appendTimestamp()
+	slago.AppendCaller(r)
+
 	var msg string
 	if len(originMsg) != 0 {
 		msg = originMsg[0]
 	}
 	r.event.Msg(msg)
-	recordPool.Put(r)
+	r.finish(msg)
 }
 
 func (r *zeroRecord) Msgf(format string, v ...interface{}) {
-	r.event.Msgf(format, v...)
+	if r.nested {
+		return
+	}
+
+	r.appendTimestamp()
+	slago.AppendCaller(r)
+
+	if r.done == nil {
+		r.event.Msgf(format, v...)
+		r.finish("")
+		return
+	}
+
+	msg := fmt.Sprintf(format, v...)
+	r.event.Msg(msg)
+	r.finish(msg)
+}
+
+func (r *zeroRecord) Msgt(template string, args ...interface{}) {
+	if r.nested {
+		return
+	}
+
+	r.Msg(slago.AppendTemplate(r, template, args...))
+}
+
+// finish puts the record back to pool, and calls done if the record terminates process.
+func (r *zeroRecord) finish(msg string) {
+	done := r.done
 	recordPool.Put(r)
+	if done != nil {
+		done(msg)
+	}
+}
+
+// exitAfterShutdown shuts down slago, then exits the process.
+func exitAfterShutdown(string) {
+	slago.ShutdownBeforeExit()
+	os.Exit(1)
+}
+
+// panicAfterShutdown shuts down slago, then panics with the message.
+func panicAfterShutdown(msg string) {
+	slago.ShutdownBeforeExit()
+	panic(msg)
+}
+
+// appendTimestamp appends the given event time, or current time if not set.
+func (r *zeroRecord) appendTimestamp() {
+	if r.time.IsZero() {
+		r.event.Timestamp()
+	} else {
+		r.event.Time(zerolog.TimestampFieldName, r.time)
+	}
+}
+
+// objectMarshaler adapts slago.ObjectMarshaler to zerolog.LogObjectMarshaler.
+type objectMarshaler struct {
+	obj slago.ObjectMarshaler
+}
+
+func (m *objectMarshaler) MarshalZerologObject(e *zerolog.Event) {
+	_ = m.obj.MarshalSlagoObject(newNestedRecord(e))
+}
+
+// newArray encodes slago.ArrayMarshaler into zerolog array.
+func newArray(arr slago.ArrayMarshaler) *zerolog.Array {
+	a := zerolog.Arr()
+	_ = arr.MarshalSlagoArray(&arrayEncoder{a})
+	return a
+}
+
+// arrayEncoder adapts zerolog.Array to slago.ArrayEncoder.
+type arrayEncoder struct {
+	arr *zerolog.Array
+}
+
+func (e *arrayEncoder) AppendStr(val string) {
+	e.arr.Str(val)
+}
+
+func (e *arrayEncoder) AppendBool(val bool) {
+	e.arr.Bool(val)
+}
+
+func (e *arrayEncoder) AppendInt(val int) {
+	e.arr.Int(val)
+}
+
+func (e *arrayEncoder) AppendInt64(val int64) {
+	e.arr.Int64(val)
+}
+
+func (e *arrayEncoder) AppendUint(val uint) {
+	e.arr.Uint(val)
+}
+
+func (e *arrayEncoder) AppendUint64(val uint64) {
+	e.arr.Uint64(val)
+}
+
+func (e *arrayEncoder) AppendFloat64(val float64) {
+	e.arr.Float64(val)
+}
+
+func (e *arrayEncoder) AppendTime(val time.Time) {
+	e.arr.Time(val)
+}
+
+func (e *arrayEncoder) AppendDur(val time.Duration) {
+	e.arr.Dur(val)
+}
+
+func (e *arrayEncoder) AppendObject(obj slago.ObjectMarshaler) {
+	e.arr.Object(&objectMarshaler{obj})
+}
+
+func (e *arrayEncoder) AppendInterface(val interface{}) {
+	e.arr.Interface(val)
 }
@@ -28,6 +28,7 @@ type blockingQueue struct {
 	count     int
 	takeIndex int
 	putIndex  int
+	closed    bool
 }
 
 // NewBlockingQueue creates a new blocking queue.
@@ -60,9 +61,12 @@ func (q *blockingQueue) Put(item []byte) {
 	q.locker.Lock()
 	defer q.locker.Unlock()
 
-	for q.count == len(q.items) {
+	for q.count == len(q.items) && !q.closed {
 		q.notFull.Wait()
 	}
+	if q.closed {
+		return
+	}
 
 	q.items[q.putIndex].Write(item)
 	q.putIndex++
@@ -74,14 +78,17 @@ func (q *blockingQueue) Put(item []byte) {
 	q.notEmpty.Signal()
 }
 
-// Take takes an item from queue.
-func (q *blockingQueue) Take() []byte {
+// Take takes an item from queue, false will be returned if the queue is closed.
+func (q *blockingQueue) Take() ([]byte, bool) {
 	q.locker.Lock()
 	defer q.locker.Unlock()
 
-	for q.count == 0 {
+	for q.count == 0 && !q.closed {
 		q.notEmpty.Wait()
 	}
+	if q.closed {
+		return nil, false
+	}
 
 	next := q.items[q.takeIndex]
 	q.takeIndex++
@@ -95,5 +102,25 @@ func (q *blockingQueue) Take() []byte {
 
 	q.notFull.Signal()
 
-	return data
+	return data, true
+}
+
+// Close closes the queue and discards the items left, then returns the count of
+// discarded items. The goroutines blocked in Put or Take will be woken up.
+func (q *blockingQueue) Close() int {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	discarded := q.count
+	for _, item := range q.items {
+		item.Reset()
+	}
+	q.count = 0
+	q.takeIndex = 0
+	q.putIndex = 0
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+
+	return discarded
 }
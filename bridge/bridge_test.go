@@ -0,0 +1,73 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/coolerfall/slago"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBridge(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "bridge test")
+}
+
+var _ = Describe("logrus bridge", func() {
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		buf = new(bytes.Buffer)
+		Expect(slago.Bind(slago.NewNativeLogger())).To(BeNil())
+		slago.Logger().AddWriter(&bufferWriter{buf})
+		Expect(slago.Install(NewLogrusBridge())).To(BeNil())
+	})
+	AfterEach(func() {
+		slago.DefaultContext().Reset()
+	})
+
+	It("keep sub-second timestamp", func() {
+		logrus.WithField("key", "value").Warn("hello")
+		Expect(jsonparser.GetString(buf.Bytes(), slago.MessageFieldKey)).To(Equal("hello"))
+		Expect(jsonparser.GetString(buf.Bytes(), slago.LevelFieldKey)).To(Equal("WARN"))
+		ts, err := jsonparser.GetString(buf.Bytes(), slago.TimestampFieldKey)
+		Expect(err).To(BeNil())
+		t, err := time.Parse(slago.TimestampFormat, ts)
+		Expect(err).To(BeNil())
+		Expect(t.Nanosecond()).NotTo(BeZero())
+	})
+})
+
+type bufferWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *bufferWriter) Write(p []byte) (n int, err error) {
+	return w.buf.Write(p)
+}
+
+func (w *bufferWriter) Encoder() slago.Encoder {
+	return nil
+}
+
+func (w *bufferWriter) Filter() slago.Filter {
+	return nil
+}
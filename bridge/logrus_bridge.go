@@ -17,7 +17,6 @@ package bridge
 import (
 	"bytes"
 	"sync"
-	"time"
 
 	"github.com/coolerfall/slago"
 	"github.com/sirupsen/logrus"
@@ -45,7 +44,7 @@ func NewLogrusBridge() slago.Bridge {
 		buf: new(bytes.Buffer),
 	}
 	logrus.SetFormatter(&logrus.JSONFormatter{
-		TimestampFormat: time.RFC3339,
+		TimestampFormat: slago.TimestampFormat,
 		FieldMap: logrus.FieldMap{
 			logrus.FieldKeyLevel: slago.LevelFieldKey,
 			logrus.FieldKeyTime:  slago.TimestampFieldKey,
@@ -65,7 +64,7 @@ func (b *logrusBridge) Name() string {
 func (b *logrusBridge) ParseLevel(lvl string) slago.Level {
 	level, err := logrus.ParseLevel(lvl)
 	if err != nil {
-		slago.Reportf("parse logrus level error: %s", err)
+		slago.DefaultStatusManager().Error("logrus bridge", "parse level error: %v", err)
 		level = logrus.TraceLevel
 	}
 
@@ -90,7 +89,7 @@ func (b *logrusBridge) Write(p []byte) (int, error) {
 
 	err := slago.BrigeWrite(b, p)
 	if err != nil {
-		slago.Reportf("logrus bridge write error", err)
+		slago.DefaultStatusManager().Error("logrus bridge", "write error: %v", err)
 	}
 
 	return len(p), err
@@ -59,7 +59,7 @@ func (b *zapBridge) Name() string {
 func (b *zapBridge) ParseLevel(lvl string) slago.Level {
 	var level = zapcore.DebugLevel
 	if err := (&level).UnmarshalText([]byte(lvl)); err != nil {
-		slago.Reportf("parse zap level error: %s", err)
+		slago.DefaultStatusManager().Error("zap bridge", "parse level error: %v", err)
 	}
 
 	return zapLvlToSlagoLvl[level]
@@ -68,7 +68,7 @@ func (b *zapBridge) ParseLevel(lvl string) slago.Level {
 func (b *zapBridge) Write(p []byte) (int, error) {
 	err := slago.BrigeWrite(b, p)
 	if err != nil {
-		slago.Reportf("zap bridge write error", err)
+		slago.DefaultStatusManager().Error("zap bridge", "write error: %v", err)
 	}
 
 	return len(p), err
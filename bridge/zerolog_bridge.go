@@ -57,7 +57,7 @@ func (b *zerologBridge) ParseLevel(lvl string) slago.Level {
 	level, err := zerolog.ParseLevel(lvl)
 	if err != nil {
 		level = zerolog.TraceLevel
-		slago.Reportf("parse zerolog level error: %s", err)
+		slago.DefaultStatusManager().Error("zerolog bridge", "parse level error: %v", err)
 	}
 
 	return zeroLvlToSlagoLvl[level]
@@ -66,7 +66,7 @@ func (b *zerologBridge) ParseLevel(lvl string) slago.Level {
 func (b *zerologBridge) Write(p []byte) (int, error) {
 	err := slago.BrigeWrite(b, p)
 	if err != nil {
-		slago.Reportf("zerolog bridge write error", err)
+		slago.DefaultStatusManager().Error("zerolog bridge", "write error: %v", err)
 	}
 
 	return len(p), err
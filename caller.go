@@ -0,0 +1,160 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const maxCallerDepth = 32
+
+var (
+	callerEnabled int32
+	callerSkip    int32
+
+	skipPackagesLocker sync.RWMutex
+	// frames in these packages will be skipped when finding caller, so the
+	// bridges and binders will report the real call site.
+	skipPackages = map[string]bool{
+		"github.com/coolerfall/slago":                  true,
+		"github.com/coolerfall/slago/binder/slazap":    true,
+		"github.com/coolerfall/slago/binder/slazero":   true,
+		"github.com/coolerfall/slago/binder/slalogrus": true,
+		"github.com/coolerfall/slago/binder/slanative": true,
+		"github.com/coolerfall/slago/bridge":           true,
+		"github.com/buger/jsonparser":                  true,
+		"github.com/rs/zerolog":                        true,
+		"github.com/rs/zerolog/log":                    true,
+		"github.com/sirupsen/logrus":                   true,
+		"go.uber.org/zap":                              true,
+		"go.uber.org/zap/zapcore":                      true,
+		"log":                                          true,
+	}
+)
+
+// EnableCaller enables caller capture for all records. The skip is the number
+// of extra stack frames to skip, which is useful for custom logging wrappers.
+func EnableCaller(skip int) {
+	atomic.StoreInt32(&callerSkip, int32(skip))
+	atomic.StoreInt32(&callerEnabled, 1)
+}
+
+// DisableCaller disables caller capture.
+func DisableCaller() {
+	atomic.StoreInt32(&callerEnabled, 0)
+}
+
+// AddCallerSkipPackages adds packages whose frames will be skipped when
+// capturing caller, e.g. the package of a logging wrapper.
+func AddCallerSkipPackages(pkgs ...string) {
+	skipPackagesLocker.Lock()
+	defer skipPackagesLocker.Unlock()
+
+	for _, pkg := range pkgs {
+		skipPackages[pkg] = true
+	}
+}
+
+// AppendCaller appends caller into record if caller capture is enabled.
+// This is used by slago logger implementations before outputting log.
+func AppendCaller(r Record) Record {
+	if atomic.LoadInt32(&callerEnabled) == 0 {
+		return r
+	}
+
+	caller, ok := Caller(int(atomic.LoadInt32(&callerSkip)))
+	if !ok {
+		return r
+	}
+
+	return r.Str(CallerFieldKey, caller)
+}
+
+// Caller gets the first caller outside slago, bridges and logging frameworks,
+// then skips the given frames. The caller is formatted as function(file:line).
+func Caller(skip int) (string, bool) {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	skipPackagesLocker.RLock()
+	defer skipPackagesLocker.RUnlock()
+
+	for {
+		frame, more := frames.Next()
+		if !skipPackages[funcPackage(frame.Function)] {
+			if skip <= 0 {
+				return formatFrame(frame), true
+			}
+			skip--
+		}
+
+		if !more {
+			return "", false
+		}
+	}
+}
+
+// formatFrame formats frame as function(file:line).
+func formatFrame(frame runtime.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(frame.Function)
+	sb.WriteByte('(')
+	sb.WriteString(frame.File)
+	sb.WriteByte(':')
+	sb.WriteString(strconv.Itoa(frame.Line))
+	sb.WriteByte(')')
+
+	return sb.String()
+}
+
+// parseFrame parses frame formatted as function(file:line).
+func parseFrame(p []byte) (fn, file, line []byte) {
+	if len(p) == 0 {
+		return
+	}
+
+	start := bytes.LastIndexByte(p, '(')
+	if start < 0 || p[len(p)-1] != ')' {
+		return nil, p, nil
+	}
+
+	fn = p[:start]
+	file = p[start+1 : len(p)-1]
+	if i := bytes.LastIndexByte(file, ':'); i >= 0 {
+		line = file[i+1:]
+		file = file[:i]
+	}
+
+	return fn, file, line
+}
+
+// funcPackage gets the package name of given function name.
+func funcPackage(fn string) string {
+	index := 0
+	if i := strings.LastIndex(fn, "/"); i >= 0 {
+		index = i
+	}
+	if i := strings.Index(fn[index:], "."); i >= 0 {
+		index += i
+	}
+
+	return fn[:index]
+}
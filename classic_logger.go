@@ -14,12 +14,33 @@
 
 package slago
 
+import (
+	"context"
+	"math"
+	"sync/atomic"
+
+	"github.com/buger/jsonparser"
+)
+
 // classicLogger represents a classic logger with name which can be used as category.
 type classicLogger struct {
 	name   string
 	root   SlaLogger
 	parent SlaLogger
-	lvl    Level
+	lvl    int32
+	// lvlFixed means the level is fixed by environment variables or flags
+	lvlFixed int32
+	output   *loggerOutput
+}
+
+// levelNotSet means the level is inherited from the nearest ancestor with level set.
+const levelNotSet int32 = math.MinInt32
+
+// loggerOutput represents the writers attached to a named logger.
+type loggerOutput struct {
+	writer   *MultiWriter
+	additive int32
+	fixed    int32
 }
 
 // newClassicLogger creates a new instance of classic logger.
@@ -28,7 +49,11 @@ func newClassicLogger(name string, root SlaLogger, parent SlaLogger) SlaLogger {
 		name:   name,
 		root:   root,
 		parent: parent,
-		lvl:    TraceLevel,
+		lvl:    levelNotSet,
+		output: &loggerOutput{
+			writer:   NewMultiWriter(),
+			additive: 1,
+		},
 	}
 }
 
@@ -37,15 +62,111 @@ func (cl *classicLogger) Name() string {
 }
 
 func (cl *classicLogger) AddWriter(w ...Writer) {
-	cl.parent.AddWriter(w...)
+	// the writers overridden by environment or flags can't be changed
+	if atomic.LoadInt32(&cl.output.fixed) == 1 {
+		return
+	}
+	cl.output.writer.AddWriter(w...)
 }
 
 func (cl *classicLogger) ResetWriter() {
-	cl.parent.ResetWriter()
+	cl.output.writer.Reset()
 }
 
+// fixWriters replaces all the writers with the given writer, and the writers
+// added later will be ignored.
+func (cl *classicLogger) fixWriters(w Writer) {
+	if lc, ok := w.(Lifecycle); ok {
+		if err := lc.Start(); err != nil {
+			defaultStatusManager.Error("writer", "start writer error: %v", err)
+			return
+		}
+	}
+	atomic.StoreInt32(&cl.output.fixed, 1)
+	stopWriters(cl.output.writer.swap([]Writer{w}), map[string]Writer{"": w})
+}
+
+// SetAdditive sets if the events of current logger will be written into the
+// writers of ancestors. Loggers are additive by default.
+func (cl *classicLogger) SetAdditive(additive bool) {
+	var val int32
+	if additive {
+		val = 1
+	}
+	atomic.StoreInt32(&cl.output.additive, val)
+}
+
+func (cl *classicLogger) additive() bool {
+	return atomic.LoadInt32(&cl.output.additive) == 1
+}
+
+// SetLevel sets the level of current logger, it takes no effect if the level
+// is fixed by environment variables or flags.
 func (cl *classicLogger) SetLevel(lvl Level) {
-	cl.lvl = lvl
+	if cl.levelFixed() {
+		return
+	}
+	atomic.StoreInt32(&cl.lvl, int32(lvl))
+}
+
+// ResetLevel resets the level of current logger, then the level will be
+// inherited from the nearest ancestor. The level of root logger will be
+// reset to trace level. It takes no effect if the level is fixed.
+func (cl *classicLogger) ResetLevel() {
+	if cl.levelFixed() {
+		return
+	}
+	atomic.StoreInt32(&cl.lvl, levelNotSet)
+}
+
+// fixLevel sets the level which cannot be changed by SetLevel or ResetLevel.
+func (cl *classicLogger) fixLevel(lvl Level) {
+	atomic.StoreInt32(&cl.lvl, int32(lvl))
+	atomic.StoreInt32(&cl.lvlFixed, 1)
+}
+
+// unfixLevel makes the level changeable again, the fixed level is kept.
+func (cl *classicLogger) unfixLevel() {
+	atomic.StoreInt32(&cl.lvlFixed, 0)
+}
+
+func (cl *classicLogger) levelFixed() bool {
+	return atomic.LoadInt32(&cl.lvlFixed) == 1
+}
+
+// levelSet gets the level set in current logger, false will be returned if not set.
+func (cl *classicLogger) levelSet() (Level, bool) {
+	lvl := atomic.LoadInt32(&cl.lvl)
+	if lvl == levelNotSet {
+		return TraceLevel, false
+	}
+
+	return Level(lvl), true
+}
+
+// EffectiveLevel gets the level set in current logger or the nearest ancestor.
+func (cl *classicLogger) EffectiveLevel() Level {
+	for l := cl; l != nil; l, _ = l.parent.(*classicLogger) {
+		if lvl, ok := l.levelSet(); ok {
+			return lvl
+		}
+	}
+
+	return TraceLevel
+}
+
+func (cl *classicLogger) With() *FieldContext {
+	ctx := cl.root.With()
+	return NewFieldContext(ctx.record, func() SlaLogger {
+		// the child logger shares the same name and writers, and inherits level from current logger
+		child := newClassicLogger(cl.name, ctx.Logger(), cl).(*classicLogger)
+		child.output = cl.output
+		return child
+	})
+}
+
+func (cl *classicLogger) Ctx(ctx context.Context) SlaLogger {
+	return cl.With().Ctx(ctx).Logger()
 }
 
 func (cl *classicLogger) Trace() Record {
@@ -60,6 +181,10 @@ func (cl *classicLogger) Info() Record {
 	return cl.makeRecord(InfoLevel, cl.root.Info)
 }
 
+func (cl *classicLogger) Notice() Record {
+	return cl.makeRecord(NoticeLevel, cl.root.Notice)
+}
+
 func (cl *classicLogger) Warn() Record {
 	return cl.makeRecord(WarnLevel, cl.root.Warn)
 }
@@ -76,8 +201,31 @@ func (cl *classicLogger) Panic() Record {
 	return cl.makeRecord(PanicLevel, cl.root.Panic)
 }
 
+func (cl *classicLogger) Level(lvl Level) Record {
+	return cl.makeRecord(lvl, func() Record {
+		return cl.root.Level(lvl)
+	})
+}
+
 func (cl *classicLogger) WriteRaw(p []byte) {
-	cl.parent.WriteRaw(p)
+	cl.root.WriteRaw(p)
+}
+
+// write writes the event into the writers of current logger and the writers
+// of ancestors until a logger which is not additive is reached.
+func (cl *classicLogger) write(p []byte) (n int, err error) {
+	for l := cl; l != nil; {
+		if n, err = l.output.writer.write(p); err != nil {
+			return
+		}
+
+		if !l.additive() {
+			break
+		}
+		l, _ = l.parent.(*classicLogger)
+	}
+
+	return len(p), nil
 }
 
 func (cl *classicLogger) makeRecord(lvl Level, newRecord func() Record) Record {
@@ -89,27 +237,55 @@ func (cl *classicLogger) makeRecord(lvl Level, newRecord func() Record) Record {
 		record = newNoopRecord()
 	}
 
-	// append logger name
+	// append logger name, root logger has no name as the bound logger
+	if cl.name == RootLoggerName {
+		return record
+	}
 	return record.Str(LoggerFieldKey, cl.name)
 }
 
 func (cl *classicLogger) checkLevel(lvl Level) bool {
-	if cl.lvl > lvl {
-		return false
-	}
+	return lvl < OffLevel && lvl >= cl.EffectiveLevel()
+}
 
-	for l := cl; l != nil; {
-		p, ok := l.parent.(*classicLogger)
-		if !ok {
-			break
-		}
+// dispatchWriter dispatches the events written by bound slago logger into the
+// writers attached to the named logger. The events are written into the given
+// logger directly, or the named logger is looked up with the logger name in
+// events if the bound slago logger doesn't implement OutputLogger.
+type dispatchWriter struct {
+	lc     *LoggerContext
+	logger *classicLogger
+}
 
-		if cl.lvl > l.lvl {
-			return false
-		}
+func newDispatchWriter(lc *LoggerContext, logger *classicLogger) Writer {
+	return &dispatchWriter{
+		lc:     lc,
+		logger: logger,
+	}
+}
+
+func (w *dispatchWriter) Write(p []byte) (n int, err error) {
+	// no events will be accepted after shutdown
+	if atomic.LoadInt32(&w.lc.shutdown) == 1 {
+		return len(p), nil
+	}
+	if w.logger != nil {
+		return w.logger.write(p)
+	}
 
-		l = p
+	name, _ := jsonparser.GetString(p, LoggerFieldKey)
+	logger := w.lc.lookupLogger(name)
+	if logger == nil {
+		return len(p), nil
 	}
 
-	return true
+	return logger.write(p)
+}
+
+func (w *dispatchWriter) Encoder() Encoder {
+	return nil
+}
+
+func (w *dispatchWriter) Filter() Filter {
+	return nil
 }
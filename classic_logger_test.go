@@ -0,0 +1,157 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestClassicLogger(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "classic logger test")
+}
+
+var _ = Describe("classic logger", func() {
+	It("lazy fields", func() {
+		root := NewNativeLogger()
+		logger := newClassicLogger("test", root, root)
+		logger.SetLevel(InfoLevel)
+
+		var called bool
+		record := logger.Debug().Func("func", func() interface{} {
+			called = true
+			return "value"
+		}).Lazy(func(r Record) {
+			called = true
+		})
+		Expect(record.Enabled()).To(Equal(false))
+		Expect(called).To(Equal(false))
+	})
+	It("writers with additivity", func() {
+		native := NewNativeLogger()
+		root := newClassicLogger(RootLoggerName, native, nil).(*classicLogger)
+		db := newClassicLogger("db", native, root).(*classicLogger)
+		rootBuf, dbBuf := &bytes.Buffer{}, &bytes.Buffer{}
+		root.AddWriter(&bufferWriter{rootBuf})
+		db.AddWriter(&bufferWriter{dbBuf})
+
+		_, _ = db.write([]byte(`{"logger_name":"db"}`))
+		Expect(dbBuf.String()).To(Equal(`{"logger_name":"db"}`))
+		Expect(rootBuf.String()).To(Equal(`{"logger_name":"db"}`))
+
+		db.SetAdditive(false)
+		_, _ = db.write([]byte(`{"logger_name":"db"}`))
+		Expect(dbBuf.Len()).To(Equal(40))
+		Expect(rootBuf.Len()).To(Equal(20))
+	})
+	It("child logger with fields", func() {
+		lc := NewLoggerContext()
+		defer lc.Reset()
+		buf := &bytes.Buffer{}
+		lc.Logger().AddWriter(&bufferWriter{buf})
+
+		db := lc.Logger("db")
+		child := db.With().Str("table", "user").Logger()
+		child.Info().Msg("child")
+		Expect(buf.String()).To(ContainSubstring(`"logger_name":"db"`))
+		Expect(buf.String()).To(ContainSubstring(`"table":"user"`))
+
+		buf.Reset()
+		db.Info().Msg("parent")
+		lc.Logger("cache").Info().Msg("sibling")
+		Expect(buf.String()).To(ContainSubstring(`"logger_name":"cache"`))
+		Expect(buf.String()).NotTo(ContainSubstring(`"table"`))
+	})
+})
+
+var _ = Describe("logger level", func() {
+	It("inherit level", func() {
+		native := NewNativeLogger()
+		root := newClassicLogger(RootLoggerName, native, nil).(*classicLogger)
+		db := newClassicLogger("db", native, root).(*classicLogger)
+		pool := newClassicLogger("db/pool", native, db).(*classicLogger)
+		Expect(pool.EffectiveLevel()).To(Equal(TraceLevel))
+
+		root.SetLevel(WarnLevel)
+		Expect(pool.EffectiveLevel()).To(Equal(WarnLevel))
+		Expect(pool.checkLevel(InfoLevel)).To(Equal(false))
+
+		db.SetLevel(DebugLevel)
+		Expect(pool.EffectiveLevel()).To(Equal(DebugLevel))
+		Expect(pool.checkLevel(InfoLevel)).To(Equal(true))
+		Expect(root.checkLevel(InfoLevel)).To(Equal(false))
+
+		pool.SetLevel(OffLevel)
+		Expect(pool.checkLevel(PanicLevel)).To(Equal(false))
+
+		pool.ResetLevel()
+		db.ResetLevel()
+		Expect(pool.EffectiveLevel()).To(Equal(WarnLevel))
+	})
+	It("level handler", func() {
+		handler := NewLevelHandler()
+		body := strings.NewReader(`{"name":"handler/test","level":"debug"}`)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", body))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(EffectiveLevel("handler/test")).To(Equal(DebugLevel))
+		Expect(EffectiveLevel("handler/test/child")).To(Equal(DebugLevel))
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=handler/test/child", nil))
+		Expect(rec.Body.String()).To(Equal(
+			`{"name":"handler/test/child","level":null,"effective_level":"DEBUG"}` + "\n"))
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=handler/unknown", nil))
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+		for _, l := range Loggers() {
+			Expect(l.Name).NotTo(Equal("handler/unknown"))
+		}
+
+		body = strings.NewReader(`{"name":"handler/test","level":null}`)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", body))
+		Expect(EffectiveLevel("handler/test")).To(Equal(EffectiveLevel(RootLoggerName)))
+
+		body = strings.NewReader(`{"name":"handler/test","level":"bad"}`)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", body))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+})
+
+type bufferWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *bufferWriter) Write(p []byte) (n int, err error) {
+	return w.buf.Write(p)
+}
+
+func (w *bufferWriter) Encoder() Encoder {
+	return nil
+}
+
+func (w *bufferWriter) Filter() Filter {
+	return nil
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ComponentKind represents the kind of component in configuration.
+type ComponentKind string
+
+const (
+	WriterComponent        ComponentKind = "writer"
+	EncoderComponent       ComponentKind = "encoder"
+	FilterComponent        ComponentKind = "filter"
+	RollingPolicyComponent ComponentKind = "rollingPolicy"
+)
+
+// ComponentConfig represents the resolved configuration to build a component.
+// The variables in properties have been substituted, and the nested components
+// have been built.
+type ComponentConfig struct {
+	Name          string
+	Properties    map[string]string
+	Encoder       Encoder
+	Filter        Filter
+	RollingPolicy RollingPolicy
+	// Ref is the referenced writer, it's used by writers wrapping other writer.
+	Ref Writer
+}
+
+// ComponentFactory builds a component with the given configuration.
+type ComponentFactory func(c *ComponentConfig) (interface{}, error)
+
+var (
+	componentLocker    sync.RWMutex
+	componentFactories = map[ComponentKind]map[string]ComponentFactory{
+		WriterComponent: {
+			"console": newConsoleWriterComponent,
+			"file":    newFileWriterComponent,
+			"async":   newAsyncWriterComponent,
+			"socket":  newSocketWriterComponent,
+		},
+		EncoderComponent: {
+			"pattern": newPatternEncoderComponent,
+			"json":    newJsonEncoderComponent,
+		},
+		FilterComponent: {
+			"level":        newLevelFilterComponent,
+			"keyword":      newKeywordFilterComponent,
+			"marker":       newMarkerFilterComponent,
+			"markerAccept": newMarkerAcceptFilterComponent,
+		},
+		RollingPolicyComponent: {
+			"noop":        newNoopRollingPolicyComponent,
+			"time":        newTimeBasedRollingPolicyComponent,
+			"sizeAndTime": newSizeAndTimeBasedRollingPolicyComponent,
+		},
+	}
+)
+
+// RegisterComponent registers a custom component factory with the type name,
+// then the component can be used in configuration file with the type name.
+// The builtin component with the same type name will be replaced.
+func RegisterComponent(kind ComponentKind, typ string, factory ComponentFactory) error {
+	componentLocker.Lock()
+	defer componentLocker.Unlock()
+
+	factories, ok := componentFactories[kind]
+	if !ok {
+		return fmt.Errorf("unknown component kind: %v", kind)
+	}
+	factories[typ] = factory
+
+	return nil
+}
+
+func lookupComponent(kind ComponentKind, typ string) (ComponentFactory, bool) {
+	componentLocker.RLock()
+	defer componentLocker.RUnlock()
+
+	factory, ok := componentFactories[kind][typ]
+	return factory, ok
+}
+
+// accept checks if the component built matches current kind.
+func (k ComponentKind) accept(v interface{}) bool {
+	var ok bool
+	switch k {
+	case WriterComponent:
+		_, ok = v.(Writer)
+	case EncoderComponent:
+		_, ok = v.(Encoder)
+	case FilterComponent:
+		_, ok = v.(Filter)
+	case RollingPolicyComponent:
+		_, ok = v.(RollingPolicy)
+	}
+
+	return ok
+}
+
+func newConsoleWriterComponent(c *ComponentConfig) (interface{}, error) {
+	return NewConsoleWriter(func(o *ConsoleWriterOption) {
+		if c.Encoder != nil {
+			o.Encoder = c.Encoder
+		}
+		o.Filter = c.Filter
+	}), nil
+}
+
+func newFileWriterComponent(c *ComponentConfig) (interface{}, error) {
+	return NewFileWriter(func(o *FileWriterOption) {
+		if c.Encoder != nil {
+			o.Encoder = c.Encoder
+		}
+		o.Filter = c.Filter
+		o.RollingPolicy = c.RollingPolicy
+		o.Filename = c.String("filename", o.Filename)
+	}), nil
+}
+
+func newAsyncWriterComponent(c *ComponentConfig) (interface{}, error) {
+	if c.Ref == nil {
+		return nil, fmt.Errorf("async writer needs a referenced writer")
+	}
+	queueSize, err := c.Int("queueSize", defaultWriterQueueSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAsyncWriter(func(o *AsyncWriterOption) {
+		o.Ref = c.Ref
+		o.QueueSize = queueSize
+	}), nil
+}
+
+func newSocketWriterComponent(c *ComponentConfig) (interface{}, error) {
+	remoteUrl, err := url.Parse(c.String("remoteUrl", ""))
+	if err != nil || len(remoteUrl.Host) == 0 {
+		return nil, fmt.Errorf("invalid remoteUrl: %v", c.Properties["remoteUrl"])
+	}
+	queueSize, err := c.Int("queueSize", defaultSocketQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	var delay time.Duration
+	if d := c.String("reconnectionDelay", ""); len(d) != 0 {
+		if delay, err = time.ParseDuration(d); err != nil {
+			return nil, fmt.Errorf("invalid reconnectionDelay: %v", d)
+		}
+	}
+
+	return NewSocketWriterE(func(o *SocketWriterOption) {
+		o.RemoteUrl = remoteUrl
+		o.QueueSize = queueSize
+		if delay > 0 {
+			o.ReconnectionDelay = delay
+		}
+		o.Filter = c.Filter
+	})
+}
+
+func newPatternEncoderComponent(c *ComponentConfig) (interface{}, error) {
+	return NewPatternEncoderE(func(o *PatternEncoderOption) {
+		o.Layout = c.String("layout", "")
+	})
+}
+
+func newJsonEncoderComponent(_ *ComponentConfig) (interface{}, error) {
+	return NewJsonEncoder(), nil
+}
+
+func newLevelFilterComponent(c *ComponentConfig) (interface{}, error) {
+	lvl, err := ParseLevelE(c.String("level", TraceLevel.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLevelFilter(lvl), nil
+}
+
+func newKeywordFilterComponent(c *ComponentConfig) (interface{}, error) {
+	return NewKeywordFilter(splitValues(c.String("keywords", ""))...), nil
+}
+
+func newMarkerFilterComponent(c *ComponentConfig) (interface{}, error) {
+	return NewMarkerFilter(splitValues(c.String("markers", ""))...), nil
+}
+
+func newMarkerAcceptFilterComponent(c *ComponentConfig) (interface{}, error) {
+	return NewMarkerAcceptFilter(splitValues(c.String("markers", ""))...), nil
+}
+
+func newNoopRollingPolicyComponent(_ *ComponentConfig) (interface{}, error) {
+	return NewNoopRollingPolicy(), nil
+}
+
+func newTimeBasedRollingPolicyComponent(c *ComponentConfig) (interface{}, error) {
+	maxHistory, err := c.Int("maxHistory", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTimeBasedRollingPolicyE(func(o *TimeBasedRPOption) {
+		o.FilenamePattern = c.String("filenamePattern", o.FilenamePattern)
+		o.MaxHistory = maxHistory
+	})
+}
+
+func newSizeAndTimeBasedRollingPolicyComponent(c *ComponentConfig) (interface{}, error) {
+	maxHistory, err := c.Int("maxHistory", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSizeAndTimeBasedRollingPolicyE(func(o *SizeAndTimeBasedRPOption) {
+		o.FilenamePattern = c.String("filenamePattern", o.FilenamePattern)
+		o.MaxFileSize = c.String("maxFileSize", o.MaxFileSize)
+		o.MaxHistory = maxHistory
+	})
+}
+
+// splitValues splits comma separated values.
+func splitValues(s string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) != 0 {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
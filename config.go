@@ -0,0 +1,670 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProfileEnvKey is the environment variable to specify active profiles in
+// configuration, multiple profiles are separated by comma.
+const ProfileEnvKey = "SLAGO_PROFILE"
+
+const defaultScanPeriod = time.Minute
+
+// Configuration represents a logback-like configuration document which
+// describes writers, encoders, filters, rolling policies and loggers.
+type Configuration struct {
+	XMLName    xml.Name        `json:"-" xml:"configuration"`
+	Scan       bool            `json:"scan" xml:"scan,attr"`
+	ScanPeriod string          `json:"scanPeriod" xml:"scanPeriod,attr"`
+	Properties Properties      `json:"properties" xml:"property"`
+	Writers    []*Component    `json:"writers" xml:"writer"`
+	Root       *LoggerConfig   `json:"root" xml:"root"`
+	Loggers    []*LoggerConfig `json:"loggers" xml:"logger"`
+	Profiles   []*Profile      `json:"profiles" xml:"profile"`
+}
+
+// Profile represents a section of configuration which is only applied when
+// the profile is active.
+type Profile struct {
+	Name       string          `json:"name" xml:"name,attr"`
+	Properties Properties      `json:"properties" xml:"property"`
+	Writers    []*Component    `json:"writers" xml:"writer"`
+	Root       *LoggerConfig   `json:"root" xml:"root"`
+	Loggers    []*LoggerConfig `json:"loggers" xml:"logger"`
+}
+
+// LoggerConfig represents the configuration of a named logger.
+type LoggerConfig struct {
+	Name     string      `json:"name" xml:"name,attr"`
+	Level    string      `json:"level" xml:"level,attr"`
+	Additive *bool       `json:"additive" xml:"additive,attr"`
+	Writers  []WriterRef `json:"writers" xml:"writer-ref"`
+}
+
+// WriterRef represents a reference to a writer by name.
+type WriterRef struct {
+	Ref string `xml:"ref,attr"`
+}
+
+// Component represents the configuration of a component, such as writer,
+// encoder, filter or rolling policy.
+type Component struct {
+	Name          string     `json:"name" xml:"name,attr"`
+	Type          string     `json:"type" xml:"type,attr"`
+	Ref           string     `json:"ref" xml:"ref,attr"`
+	Properties    Properties `json:"properties" xml:"property"`
+	Encoder       *Component `json:"encoder" xml:"encoder"`
+	Filter        *Component `json:"filter" xml:"filter"`
+	RollingPolicy *Component `json:"rollingPolicy" xml:"rollingPolicy"`
+}
+
+// Properties represents key-value properties of configuration. It's an object
+// in json, and repeated <property name="" value=""/> elements in xml.
+type Properties map[string]string
+
+// UnmarshalJSON unmarshals writer reference from a json string.
+func (r *WriterRef) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &r.Ref)
+}
+
+// UnmarshalXML unmarshals a property element into properties.
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var property struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	}
+	if err := d.DecodeElement(&property, &start); err != nil {
+		return err
+	}
+
+	if *p == nil {
+		*p = make(Properties)
+	}
+	(*p)[property.Name] = property.Value
+
+	return nil
+}
+
+// ParseConfiguration parses configuration from json or xml data.
+func ParseConfiguration(data []byte) (*Configuration, error) {
+	config := &Configuration{}
+	data = bytes.TrimSpace(data)
+	var err error
+	if bytes.HasPrefix(data, []byte("<")) {
+		err = xml.Unmarshal(data, config)
+	} else {
+		err = json.Unmarshal(data, config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse configuration error: %v", err)
+	}
+
+	return config, nil
+}
+
+// ConfigureFile reads configuration from a json or xml file, and configures
+// the default logger context with it.
+func ConfigureFile(path string) error {
+	return defaultContext.ConfigureFile(path)
+}
+
+// Configure configures the default logger context with the given configuration.
+func Configure(config *Configuration) error {
+	return defaultContext.Configure(config)
+}
+
+// ConfigureFile reads configuration from a json or xml file, and configures
+// current logger context with it.
+// If scan is enabled in configuration, the file will be watched and
+// configuration will be reloaded when the file changed.
+func (lc *LoggerContext) ConfigureFile(path string) error {
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("read configuration error: %v", err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read configuration error: %v", err)
+	}
+
+	config, err := ParseConfiguration(data)
+	if err != nil {
+		return err
+	}
+	period := defaultScanPeriod
+	if len(config.ScanPeriod) != 0 {
+		if period, err = time.ParseDuration(config.ScanPeriod); err != nil || period <= 0 {
+			return fmt.Errorf("invalid scanPeriod: %v", config.ScanPeriod)
+		}
+	}
+
+	if err = lc.Configure(config); err != nil {
+		return err
+	}
+
+	var watcher *configWatcher
+	if config.Scan {
+		watcher = newConfigWatcher(lc, path, period, info)
+	}
+	lc.setWatcher(watcher)
+
+	return nil
+}
+
+// Configure configures current logger context with the given configuration.
+// All the writers are built before applied, so the loggers won't be changed
+// if any error occurs.
+func (lc *LoggerContext) Configure(config *Configuration) error {
+	lc.configLocker.Lock()
+	defer lc.configLocker.Unlock()
+
+	resolved := config.activate(activeProfiles())
+	b := newConfigBuilder(resolved.Properties)
+
+	writers := make(map[string]Writer)
+	refs := make(map[string]string)
+	for _, c := range resolved.Writers {
+		if len(c.Name) == 0 {
+			return fmt.Errorf("writer name is required")
+		}
+		if _, ok := writers[c.Name]; ok {
+			return fmt.Errorf("duplicate writer: %v", c.Name)
+		}
+
+		w, err := b.buildWriter(c, writers)
+		if err != nil {
+			return err
+		}
+		writers[c.Name] = w
+		if len(c.Ref) != 0 {
+			refs[c.Name] = c.Ref
+		}
+	}
+
+	loggers := resolved.Loggers
+	if resolved.Root != nil {
+		root := *resolved.Root
+		root.Name = RootLoggerName
+		loggers = append([]*LoggerConfig{&root}, loggers...)
+	}
+	overrideWriter := lc.overrideWriter()
+
+	type loggerSetting struct {
+		name     string
+		logger   *classicLogger
+		level    *Level
+		additive *bool
+		writers  []Writer
+	}
+	settings := make([]loggerSetting, 0, len(loggers))
+	// only the writers referenced by loggers, directly or by other writers, are used
+	used := make(map[string]Writer)
+	for _, c := range loggers {
+		if len(c.Name) == 0 {
+			return fmt.Errorf("logger name is required")
+		}
+
+		setting := loggerSetting{
+			name:     b.substitute(c.Name),
+			additive: c.Additive,
+		}
+		if lvl := b.substitute(c.Level); len(lvl) != 0 {
+			level, err := ParseLevelE(lvl)
+			if err != nil {
+				return fmt.Errorf("logger %v: %v", c.Name, err)
+			}
+			setting.level = &level
+		}
+		for _, ref := range c.Writers {
+			w, ok := writers[ref.Ref]
+			if !ok {
+				return fmt.Errorf("logger %v: unknown writer %v", c.Name, ref.Ref)
+			}
+			setting.writers = append(setting.writers, w)
+			for name := ref.Ref; len(name) != 0; name = refs[name] {
+				used[name] = writers[name]
+			}
+		}
+		settings = append(settings, setting)
+	}
+
+	// new writers are started before replacing, and old writers are stopped
+	// after replaced, so no events will be lost during configuring
+	started := make([]Writer, 0, len(used))
+	for name, w := range used {
+		if lc, ok := w.(Lifecycle); ok {
+			if err := lc.Start(); err != nil {
+				stopWriters(started, nil)
+				return fmt.Errorf("start writer %v error: %v", name, err)
+			}
+		}
+		started = append(started, w)
+	}
+
+	// the loggers are created after all the settings are validated
+	for i := range settings {
+		s := &settings[i]
+		s.logger = lc.Logger(s.name).(*classicLogger)
+		if s.logger.name == RootLoggerName && overrideWriter != nil {
+			s.writers = []Writer{overrideWriter}
+		}
+	}
+	replaced := make([]Writer, 0)
+	configured := make(map[string]bool)
+	for _, s := range settings {
+		configured[s.logger.name] = true
+	}
+	// the loggers configured before but removed in this configuration are reset
+	for name := range lc.configured {
+		if configured[name] {
+			continue
+		}
+		logger := lc.Logger(name).(*classicLogger)
+		logger.ResetLevel()
+		logger.SetAdditive(true)
+		replaced = append(replaced, logger.output.writer.swap(nil)...)
+	}
+	lc.configured = configured
+	for _, s := range settings {
+		if s.level == nil {
+			s.logger.ResetLevel()
+		} else {
+			s.logger.SetLevel(*s.level)
+		}
+		s.logger.SetAdditive(s.additive == nil || *s.additive)
+		replaced = append(replaced, s.logger.output.writer.swap(s.writers)...)
+	}
+	if overrideWriter != nil {
+		used[""] = overrideWriter
+	}
+	stopWriters(replaced, used)
+	lc.applyOverrides()
+
+	return nil
+}
+
+// stopWriters stops the writers which are not in use.
+func stopWriters(writers []Writer, inUse map[string]Writer) {
+	stopped := make(map[Writer]bool)
+	for _, w := range writers {
+		if stopped[w] || containsWriter(inUse, w) {
+			continue
+		}
+
+		stopped[w] = true
+		if lc, ok := w.(Lifecycle); ok {
+			lc.Stop()
+		}
+	}
+}
+
+func containsWriter(writers map[string]Writer, w Writer) bool {
+	for _, v := range writers {
+		if v == w {
+			return true
+		}
+	}
+
+	return false
+}
+
+// activate merges the sections of active profiles into configuration.
+func (c *Configuration) activate(profiles []string) *Configuration {
+	resolved := &Configuration{
+		Properties: make(Properties),
+		Writers:    append([]*Component{}, c.Writers...),
+		Root:       c.Root,
+		Loggers:    append([]*LoggerConfig{}, c.Loggers...),
+	}
+	for k, v := range c.Properties {
+		resolved.Properties[k] = v
+	}
+
+	for _, p := range c.Profiles {
+		if !containsString(profiles, p.Name) {
+			continue
+		}
+
+		for k, v := range p.Properties {
+			resolved.Properties[k] = v
+		}
+		for _, w := range p.Writers {
+			resolved.Writers = replaceComponent(resolved.Writers, w)
+		}
+		if p.Root != nil {
+			resolved.Root = p.Root
+		}
+		for _, l := range p.Loggers {
+			resolved.Loggers = replaceLogger(resolved.Loggers, l)
+		}
+	}
+
+	return resolved
+}
+
+func replaceComponent(components []*Component, c *Component) []*Component {
+	for i, old := range components {
+		if old.Name == c.Name {
+			components[i] = c
+			return components
+		}
+	}
+
+	return append(components, c)
+}
+
+func replaceLogger(loggers []*LoggerConfig, l *LoggerConfig) []*LoggerConfig {
+	for i, old := range loggers {
+		if old.Name == l.Name {
+			loggers[i] = l
+			return loggers
+		}
+	}
+
+	return append(loggers, l)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// activeProfiles gets active profiles from environment variable.
+func activeProfiles() []string {
+	profiles := make([]string, 0)
+	for _, p := range strings.Split(os.Getenv(ProfileEnvKey), ",") {
+		if p = strings.TrimSpace(p); len(p) != 0 {
+			profiles = append(profiles, p)
+		}
+	}
+
+	return profiles
+}
+
+// substituteVariables replaces ${NAME} and ${NAME:-default} in the given
+// string with properties or environment variables.
+func substituteVariables(s string, properties Properties) string {
+	var buf strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end == -1 {
+			break
+		}
+		end += start
+
+		buf.WriteString(s[:start])
+		name, def := s[start+2:end], ""
+		if i := strings.Index(name, ":-"); i != -1 {
+			name, def = name[:i], name[i+2:]
+		}
+		if v, ok := properties[name]; ok {
+			buf.WriteString(v)
+		} else if v, ok := os.LookupEnv(name); ok {
+			buf.WriteString(v)
+		} else {
+			buf.WriteString(def)
+		}
+		s = s[end+1:]
+	}
+	buf.WriteString(s)
+
+	return buf.String()
+}
+
+// configBuilder builds components from configuration.
+type configBuilder struct {
+	properties Properties
+}
+
+func newConfigBuilder(properties Properties) *configBuilder {
+	b := &configBuilder{
+		properties: make(Properties),
+	}
+	// properties can refer to the properties defined before or environment variables
+	for k, v := range properties {
+		b.properties[k] = substituteVariables(v, properties)
+	}
+
+	return b
+}
+
+func (b *configBuilder) substitute(s string) string {
+	return substituteVariables(s, b.properties)
+}
+
+func (b *configBuilder) buildWriter(c *Component, writers map[string]Writer) (Writer, error) {
+	cc, err := b.buildConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Ref) != 0 {
+		ref, ok := writers[c.Ref]
+		if !ok {
+			return nil, fmt.Errorf("writer %v: unknown writer %v", c.Name, c.Ref)
+		}
+		cc.Ref = ref
+	}
+
+	v, err := b.build(WriterComponent, c.Type, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(Writer), nil
+}
+
+func (b *configBuilder) buildConfig(c *Component) (*ComponentConfig, error) {
+	cc := &ComponentConfig{
+		Name:       c.Name,
+		Properties: make(map[string]string),
+	}
+	for k, v := range c.Properties {
+		cc.Properties[k] = b.substitute(v)
+	}
+
+	if c.Encoder != nil {
+		v, err := b.buildNested(EncoderComponent, c.Encoder)
+		if err != nil {
+			return nil, err
+		}
+		cc.Encoder = v.(Encoder)
+	}
+	if c.Filter != nil {
+		v, err := b.buildNested(FilterComponent, c.Filter)
+		if err != nil {
+			return nil, err
+		}
+		cc.Filter = v.(Filter)
+	}
+	if c.RollingPolicy != nil {
+		v, err := b.buildNested(RollingPolicyComponent, c.RollingPolicy)
+		if err != nil {
+			return nil, err
+		}
+		cc.RollingPolicy = v.(RollingPolicy)
+	}
+
+	return cc, nil
+}
+
+func (b *configBuilder) buildNested(kind ComponentKind, c *Component) (interface{}, error) {
+	cc, err := b.buildConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.build(kind, c.Type, cc)
+}
+
+func (b *configBuilder) build(kind ComponentKind, typ string,
+	cc *ComponentConfig) (interface{}, error) {
+	typ = b.substitute(typ)
+	factory, ok := lookupComponent(kind, typ)
+	if !ok {
+		return nil, fmt.Errorf("unknown %v type: %q", kind, typ)
+	}
+
+	v, err := factory(cc)
+	if err != nil {
+		return nil, fmt.Errorf("build %v %q error: %v", kind, typ, err)
+	}
+	if !kind.accept(v) {
+		return nil, fmt.Errorf("%v %q built an invalid component %T", kind, typ, v)
+	}
+
+	return v, nil
+}
+
+// Int gets int property with the given key, def will be returned if not set.
+func (c *ComponentConfig) Int(key string, def int) (int, error) {
+	v, ok := c.Properties[key]
+	if !ok || len(v) == 0 {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def, fmt.Errorf("invalid %v: %v", key, v)
+	}
+
+	return n, nil
+}
+
+// String gets string property with the given key, def will be returned if not set.
+func (c *ComponentConfig) String(key string, def string) string {
+	v, ok := c.Properties[key]
+	if !ok || len(v) == 0 {
+		return def
+	}
+
+	return v
+}
+
+// configWatcher watches the modification of configuration file by polling,
+// and reloads the configuration when the file changed.
+type configWatcher struct {
+	lc      *LoggerContext
+	path    string
+	period  time.Duration
+	modTime time.Time
+	size    int64
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newConfigWatcher(lc *LoggerContext, path string, period time.Duration,
+	info os.FileInfo) *configWatcher {
+	return &configWatcher{
+		lc:      lc,
+		path:    path,
+		period:  period,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		done:    make(chan struct{}),
+	}
+}
+
+func (w *configWatcher) Start() {
+	go w.watch()
+}
+
+func (w *configWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+}
+
+func (w *configWatcher) watch() {
+	ticker := time.NewTicker(w.period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(w.path)
+		if err != nil {
+			defaultStatusManager.Error("configuration", "watch error: %v", err)
+			continue
+		}
+		if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+			continue
+		}
+		w.modTime = info.ModTime()
+		w.size = info.Size()
+
+		// the previous configuration will be kept if any error occurs
+		if err = w.lc.reloadFile(w.path, w); err != nil {
+			defaultStatusManager.Error("configuration", "reload error: %v", err)
+		} else {
+			defaultStatusManager.Info("configuration", "reloaded from %v", w.path)
+		}
+	}
+}
+
+// reloadFile reloads configuration file if the watcher is still in use.
+func (lc *LoggerContext) reloadFile(path string, w *configWatcher) error {
+	lc.locker.Lock()
+	current := lc.watcher
+	lc.locker.Unlock()
+	if current != w {
+		return nil
+	}
+
+	return lc.ConfigureFile(path)
+}
+
+// setWatcher replaces the watcher of configuration file.
+func (lc *LoggerContext) setWatcher(w *configWatcher) {
+	lc.locker.Lock()
+	old := lc.watcher
+	lc.watcher = w
+	lc.locker.Unlock()
+
+	if old == w {
+		return
+	}
+	if old != nil {
+		old.Stop()
+	}
+	if w != nil {
+		w.Start()
+	}
+}
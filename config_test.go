@@ -0,0 +1,247 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "config test")
+}
+
+var testBuffers = make(map[string]*bytes.Buffer)
+
+var _ = RegisterComponent(WriterComponent, "buffer", func(c *ComponentConfig) (interface{}, error) {
+	buf := &bytes.Buffer{}
+	testBuffers[c.Name] = buf
+	return &bufferWriter{buf}, nil
+})
+
+var testLifecycles = make(map[string]*lifecycleWriter)
+
+var _ = RegisterComponent(WriterComponent, "lifecycle", func(c *ComponentConfig) (interface{}, error) {
+	w := &lifecycleWriter{
+		bufferWriter: bufferWriter{&bytes.Buffer{}},
+		fail:         c.Properties["fail"] == "true",
+	}
+	testLifecycles[c.Name] = w
+	return w, nil
+})
+
+var _ = Describe("configuration", func() {
+	const jsonConfig = `{
+  "properties": {"LAYOUT": "#level #message"},
+  "writers": [
+    {"name": "console", "type": "console",
+     "encoder": {"type": "pattern", "properties": {"layout": "${LAYOUT}"}},
+     "filter": {"type": "level", "properties": {"level": "${LEVEL:-info}"}}},
+    {"name": "db", "type": "buffer"}
+  ],
+  "root": {"level": "warn", "writers": ["console"]},
+  "loggers": [{"name": "acme/db", "level": "debug", "additive": false, "writers": ["db"]}],
+  "profiles": [{"name": "dev", "root": {"level": "trace", "writers": ["console"]}}]
+}`
+	const xmlConfig = `
+<configuration>
+  <property name="LAYOUT" value="#level #message"/>
+  <writer name="console" type="console">
+    <encoder type="pattern"><property name="layout" value="${LAYOUT}"/></encoder>
+    <filter type="level"><property name="level" value="${LEVEL:-info}"/></filter>
+  </writer>
+  <writer name="db" type="buffer"/>
+  <root level="warn"><writer-ref ref="console"/></root>
+  <logger name="acme/db" level="debug" additive="false"><writer-ref ref="db"/></logger>
+  <profile name="dev"><root level="trace"><writer-ref ref="console"/></root></profile>
+</configuration>`
+
+	It("parse json and xml", func() {
+		fromJson, err := ParseConfiguration([]byte(jsonConfig))
+		Expect(err).To(BeNil())
+		fromXml, err := ParseConfiguration([]byte(xmlConfig))
+		Expect(err).To(BeNil())
+		fromXml.XMLName = fromJson.XMLName
+		Expect(fromXml).To(Equal(fromJson))
+		Expect(fromJson.Writers[0].Encoder.Properties["layout"]).To(Equal("${LAYOUT}"))
+		Expect(fromJson.Loggers[0].Writers[0].Ref).To(Equal("db"))
+	})
+	It("configure", func() {
+		config, _ := ParseConfiguration([]byte(jsonConfig))
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		Expect(lc.Configure(config)).To(BeNil())
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(WarnLevel))
+		Expect(lc.EffectiveLevel("acme/db/pool")).To(Equal(DebugLevel))
+
+		_, _ = newDispatchWriter(lc, nil).Write([]byte(`{"logger_name":"acme/db/pool"}`))
+		Expect(testBuffers["db"].String()).To(Equal(`{"logger_name":"acme/db/pool"}`))
+	})
+	It("configure with profile", func() {
+		config, _ := ParseConfiguration([]byte(xmlConfig))
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		_ = os.Setenv(ProfileEnvKey, "dev")
+		defer os.Unsetenv(ProfileEnvKey)
+		Expect(lc.Configure(config)).To(BeNil())
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(TraceLevel))
+	})
+	It("configure error", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		config, _ := ParseConfiguration([]byte(`{"writers":[{"name":"x","type":"unknown"}]}`))
+		Expect(lc.Configure(config)).NotTo(BeNil())
+		config, _ = ParseConfiguration([]byte(`{"root":{"writers":["missing"]}}`))
+		Expect(lc.Configure(config)).NotTo(BeNil())
+		_, err := ParseConfiguration([]byte(`{"writers":`))
+		Expect(err).NotTo(BeNil())
+	})
+	It("start referenced writers only", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		defer lc.Reset()
+		config, _ := ParseConfiguration([]byte(`{"writers":[
+			{"name":"file","type":"lifecycle"},
+			{"name":"async","type":"async","ref":"file"},
+			{"name":"unused","type":"lifecycle"}],
+			"root":{"writers":["async"]}}`))
+		Expect(lc.Configure(config)).To(BeNil())
+		Expect(testLifecycles["file"].started).To(Equal(true))
+		Expect(testLifecycles["unused"].started).To(Equal(false))
+	})
+	It("stop started writers when failed", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		config, _ := ParseConfiguration([]byte(`{"writers":[
+			{"name":"good","type":"lifecycle"},
+			{"name":"bad","type":"lifecycle","properties":{"fail":"true"}}],
+			"loggers":[{"name":"acme/good","writers":["good"]},{"name":"acme/bad","writers":["bad"]}]}`))
+		Expect(lc.Configure(config)).NotTo(BeNil())
+		Expect(testLifecycles["good"].started).To(Equal(false))
+		Expect(testLifecycles["bad"].stopped).To(Equal(false))
+		_, ok := lc.existingLogger("acme/good")
+		Expect(ok).To(Equal(false))
+
+		config, _ = ParseConfiguration([]byte(`{"loggers":[
+			{"name":"acme/valid"},{"name":"acme/invalid","level":"unknown"}]}`))
+		Expect(lc.Configure(config)).NotTo(BeNil())
+		_, ok = lc.existingLogger("acme/valid")
+		Expect(ok).To(Equal(false))
+	})
+	It("connect socket writer when started", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		defer listener.Close()
+		var accepted int32
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				atomic.AddInt32(&accepted, 1)
+				_ = conn.Close()
+			}
+		}()
+
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		defer lc.Reset()
+		config, _ := ParseConfiguration([]byte(`{"writers":[
+			{"name":"socket","type":"socket","properties":{"remoteUrl":"ws://` + listener.Addr().String() + `"}}],
+			"root":{"writers":["missing"]}}`))
+		Expect(lc.Configure(config)).NotTo(BeNil())
+		config.Root = nil
+		Expect(lc.Configure(config)).To(BeNil())
+		time.Sleep(20 * time.Millisecond)
+		Expect(atomic.LoadInt32(&accepted)).To(Equal(int32(0)))
+	})
+	It("substitute variables", func() {
+		_ = os.Setenv("SLAGO_TEST_DIR", "/var/log")
+		defer os.Unsetenv("SLAGO_TEST_DIR")
+		props := Properties{"APP": "demo"}
+		Expect(substituteVariables("${SLAGO_TEST_DIR}/${APP}.log", props)).To(Equal("/var/log/demo.log"))
+		Expect(substituteVariables("${MISSING:-x}-${MISSING}", props)).To(Equal("x-"))
+	})
+})
+
+var _ = Describe("configuration reload", func() {
+	It("reload when changed", func() {
+		dir, _ := ioutil.TempDir("", "slago")
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "slago.json")
+		write := func(content string) {
+			_ = ioutil.WriteFile(path, []byte(content), 0644)
+			// make sure the modification time changes on file systems with low precision
+			modTime := time.Now().Add(time.Duration(len(content)) * time.Second)
+			_ = os.Chtimes(path, modTime, modTime)
+		}
+
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		defer lc.Reset()
+		write(`{"scan":true,"scanPeriod":"10ms","root":{"level":"info"},
+			"loggers":[{"name":"acme","level":"error"}]}`)
+		Expect(lc.ConfigureFile(path)).To(BeNil())
+		Expect(lc.EffectiveLevel("acme")).To(Equal(ErrorLevel))
+
+		write(`{"scan":true,"scanPeriod":"10ms","root":{"level":"warn"}}`)
+		Eventually(func() Level {
+			return lc.EffectiveLevel(RootLoggerName)
+		}).Should(Equal(WarnLevel))
+		Expect(lc.EffectiveLevel("acme")).To(Equal(WarnLevel))
+
+		// invalid configuration keeps the previous one
+		write(`{"scan":true,"root":{"level":"unknown"}} `)
+		time.Sleep(50 * time.Millisecond)
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(WarnLevel))
+
+		write(`{"scan":true,"scanPeriod":"10ms","root":{"level":"debug"}}`)
+		Eventually(func() Level {
+			return lc.EffectiveLevel(RootLoggerName)
+		}).Should(Equal(DebugLevel))
+	})
+})
+
+type lifecycleWriter struct {
+	bufferWriter
+	fail    bool
+	started bool
+	stopped bool
+}
+
+func (w *lifecycleWriter) Start() error {
+	if w.fail {
+		return errors.New("start error")
+	}
+	w.started = true
+	return nil
+}
+
+func (w *lifecycleWriter) Stop() {
+	w.started = false
+	w.stopped = true
+}
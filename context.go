@@ -0,0 +1,94 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"context"
+	"sync"
+)
+
+type fieldsKey struct{}
+
+// ContextExtractor extracts fields from context and adds them into record.
+type ContextExtractor func(ctx context.Context, r Record)
+
+var (
+	extractorLocker sync.RWMutex
+	extractors      = []ContextExtractor{extractFields}
+)
+
+// ContextWithFields returns a copy of parent context which holds the given
+// fields. The fields in parent context will be inherited and overridden.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	parent := FieldsFromContext(ctx)
+	merged := make(map[string]interface{}, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext gets the fields stored by ContextWithFields in context.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// RegisterContextExtractor registers an extractor which will be invoked
+// for every record logged with context.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	extractorLocker.Lock()
+	defer extractorLocker.Unlock()
+
+	extractors = append(extractors, extractor)
+}
+
+// ExtractContext runs all the registered extractors to add fields in
+// context into record. This is used by slago logger implementations.
+func ExtractContext(ctx context.Context, r Record) Record {
+	if ctx == nil {
+		return r
+	}
+
+	// the extractors are only appended, so a copy of slice is enough, and the
+	// extractors can log or register extractors without lock held
+	extractorLocker.RLock()
+	current := extractors
+	extractorLocker.RUnlock()
+
+	for _, extract := range current {
+		extract(ctx, r)
+	}
+
+	return r
+}
+
+// extractFields is the builtin extractor for fields stored by ContextWithFields.
+func extractFields(ctx context.Context, r Record) {
+	fields := FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return
+	}
+
+	r.Fields(fields)
+}
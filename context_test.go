@@ -0,0 +1,76 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestContext(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "context test")
+}
+
+var _ = Describe("context with fields", func() {
+	It("fields from context", func() {
+		ctx := ContextWithFields(context.Background(), map[string]interface{}{
+			"request_id": "slago",
+			"user":       "foo",
+		})
+		ctx = ContextWithFields(ctx, map[string]interface{}{
+			"user": "bar",
+		})
+		fields := FieldsFromContext(ctx)
+		Expect(fields).To(HaveLen(2))
+		Expect(fields["request_id"]).To(Equal("slago"))
+		Expect(fields["user"]).To(Equal("bar"))
+	})
+	It("no fields in context", func() {
+		Expect(FieldsFromContext(context.Background())).To(BeNil())
+	})
+	Context("register extractor in extractor", func() {
+		var saved []ContextExtractor
+		BeforeEach(func() {
+			extractorLocker.Lock()
+			saved = extractors
+			extractorLocker.Unlock()
+		})
+		AfterEach(func() {
+			extractorLocker.Lock()
+			extractors = saved
+			extractorLocker.Unlock()
+		})
+		It("no deadlock", func() {
+			var called, registered bool
+			RegisterContextExtractor(func(ctx context.Context, r Record) {
+				if registered {
+					return
+				}
+				registered = true
+				RegisterContextExtractor(func(context.Context, Record) {
+					called = true
+				})
+			})
+			ExtractContext(context.Background(), newNoopRecord())
+			ExtractContext(context.Background(), newNoopRecord())
+			Expect(registered).To(Equal(true))
+			Expect(called).To(Equal(true))
+		})
+	})
+})
@@ -15,7 +15,12 @@
 package slago
 
 import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/url"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -40,6 +45,15 @@ var _ = Describe("json encoder", func() {
 		Expect(err).To(BeNil())
 		Expect(out).To(Equal(result))
 	})
+	It("escape caller and message", func() {
+		event := makeEvent([]byte(`{"level":"INFO","time":"2019-12-27T10:40:14.465199844+08:00",` +
+			`"message":"say \"hi\"","caller":"main.main(C:\\app\\main.go:10)"}`))
+		out, err := NewJsonEncoder().Encode(event)
+		Expect(err).To(BeNil())
+		Expect(json.Valid(out)).To(Equal(true))
+		Expect(string(out)).To(ContainSubstring(`"message":"say \"hi\""`))
+		Expect(string(out)).To(ContainSubstring(`"caller":"main.main(C:\\app\\main.go:10)"`))
+	})
 })
 
 var _ = Describe("pattern encoder", func() {
@@ -55,4 +69,171 @@ var _ = Describe("pattern encoder", func() {
 		Expect(err).To(BeNil())
 		Expect(out).To(Equal(result))
 	})
+	It("invalid layout", func() {
+		_, err := NewPatternEncoderE(func(o *PatternEncoderOption) {
+			o.Layout = "#unknown #message"
+		})
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("caller converter", func() {
+	var event = makeEvent([]byte(`{"level":"INFO","message":"slago",` +
+		`"caller":"main.(*foo).bar(/go/src/slago/main.go:42)"}`))
+	It("encode caller", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#caller #caller{full} #file #line #func #message"
+		})
+		out, err := pe.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("main.go:42 /go/src/slago/main.go:42 " +
+			"main.go 42 main.(*foo).bar slago\n"))
+	})
+	It("encode without caller", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#caller #line #message"
+		})
+		out, err := pe.Encode(logEvent)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("- - -\n"))
+	})
+})
+
+var _ = Describe("exception converter", func() {
+	var event = makeEvent([]byte(`{"level":"ERROR","time":"2019-12-27T10:40:14.465199844+08:00",` +
+		`"message":"slago","stack":[` +
+		`"main.foo(/go/src/slago/main.go:42)","main.main(/go/src/slago/main.go:10)"]}`))
+	It("encode stack", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#level #message#exception"
+		})
+		out, err := pe.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("ERROR slago\n\tat main.foo(/go/src/slago/main.go:42)" +
+			"\n\tat main.main(/go/src/slago/main.go:10)\n"))
+	})
+	It("encode stack with depth", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#message#stack{1}"
+		})
+		out, err := pe.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("slago\n\tat main.foo(/go/src/slago/main.go:42)\n"))
+	})
+	It("encode stack in json", func() {
+		je := NewJsonEncoder()
+		out, err := je.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(ContainSubstring(`"stack":["main.foo(/go/src/slago/main.go:42)",` +
+			`"main.main(/go/src/slago/main.go:10)"]`))
+	})
+})
+
+var _ = Describe("fields converter", func() {
+	var event = makeEvent([]byte(`{"level":"INFO","time":"2019-12-27T10:40:14.465199844+08:00",` +
+		`"message":"slago","user":{"name":"bob","address":{"city":"ny"}},"ids":[1,2]}`))
+	It("encode nested fields", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#message #fields"
+		})
+		out, err := pe.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("slago user.name=bob user.address.city=ny ids=[1,2]\n"))
+	})
+	It("encode nested fields in json", func() {
+		je := NewJsonEncoder()
+		out, err := je.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(ContainSubstring(
+			`"user":{"name":"bob","address":{"city":"ny"}},"ids":[1,2]`))
+	})
+})
+
+var _ = Describe("cause converter", func() {
+	var event = makeEvent([]byte(`{"level":"ERROR","message":"slago","error":{` +
+		`"message":"wrap: boom","type":"*fmt.wrapError","causes":[` +
+		`{"message":"boom","type":"*errors.errorString"},` +
+		`{"message":"bang","type":"*errors.errorString"}]}}`))
+	It("encode causes", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#message#cause"
+		})
+		out, err := pe.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("slago\n*fmt.wrapError: wrap: boom" +
+			"\nCaused by: *errors.errorString: boom\nCaused by: *errors.errorString: bang\n"))
+	})
+	It("encode causes with depth", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#message#cause{1}"
+		})
+		out, err := pe.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("slago\n*fmt.wrapError: wrap: boom" +
+			"\nCaused by: *errors.errorString: boom\n"))
+	})
+	It("encode without error", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#message#cause"
+		})
+		out, err := pe.Encode(logEvent)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("-\n"))
+	})
+})
+
+var _ = Describe("marker converter", func() {
+	var event = makeEvent([]byte(`{"level":"INFO","time":"2019-12-27T10:40:14.465199844+08:00",` +
+		`"message":"slago","marker":["AUDIT"],"marker":["SECURITY"],"key":"value"}`))
+	It("encode markers", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#marker #message #fields"
+		})
+		out, err := pe.Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("AUDIT,SECURITY slago key=value\n"))
+	})
+	It("encode without markers", func() {
+		pe := NewPatternEncoder(func(o *PatternEncoderOption) {
+			o.Layout = "#marker #fields"
+		})
+		out, err := pe.Encode(logEvent)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(Equal("- key=value\n"))
+	})
+	It("encode markers in json", func() {
+		out, err := NewJsonEncoder().Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(ContainSubstring(`"message":"slago","marker":["AUDIT","SECURITY"],"key":"value"}`))
+	})
+})
+
+var _ = Describe("record fields in json encoder", func() {
+	It("encode fields", func() {
+		lc := NewLoggerContext()
+		defer lc.Reset()
+		buf := &bytes.Buffer{}
+		lc.Logger().AddWriter(&bufferWriter{buf})
+
+		_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+		mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+		u, _ := url.Parse("https://example.com/a?b=c")
+		lc.Logger().Error().RawJSON("raw", []byte(`{"a":[1,2]}`)).
+			Stringer("stringer", time.Second).Stringer("nil_stringer", nil).
+			IPAddr("ip", net.IPv4(127, 0, 0, 1)).IPPrefix("prefix", *ipNet).
+			MACAddr("mac", mac).URL("url", u).Any("any", []int{1, 2}).
+			Fields(map[string]interface{}{"b": true, "a": 1}).
+			Timestamp(time.Date(2021, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*3600))).Msg("fields")
+
+		event := makeEvent(buf.Bytes())
+		defer event.recycle()
+		out, err := NewJsonEncoder().Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(ContainSubstring(`"raw":{"a":[1,2]}`))
+		Expect(string(out)).To(ContainSubstring(`"stringer":"1s","nil_stringer":null`))
+		Expect(string(out)).To(ContainSubstring(`"ip":"127.0.0.1","prefix":"10.0.0.0/8"`))
+		Expect(string(out)).To(ContainSubstring(`"mac":"00:00:5e:00:53:01"`))
+		Expect(string(out)).To(ContainSubstring(`"url":"https://example.com/a?b=c"`))
+		Expect(string(out)).To(ContainSubstring(`"any":[1,2],"a":1,"b":true`))
+	})
 })
@@ -0,0 +1,305 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Environment variables to configure slago without code. The levels of named
+// loggers can be set with LoggerLevelsEnvKey as a list, such as
+// SLAGO_LOGGER_LEVELS=github.com/acme/db=debug,acme/cache=warn, or with
+// LevelEnvKey + "_" + logger name in which "__" stands for "/", such as
+// SLAGO_LEVEL_acme__db=debug, since shells don't allow "/" in variable names.
+const (
+	LevelEnvKey        = "SLAGO_LEVEL"
+	LoggerLevelsEnvKey = "SLAGO_LOGGER_LEVELS"
+	FormatEnvKey       = "SLAGO_FORMAT"
+	LayoutEnvKey       = "SLAGO_LAYOUT"
+	FileEnvKey         = "SLAGO_FILE"
+	BinderEnvKey       = "SLAGO_BINDER"
+)
+
+// overrides represents the settings from environment variables and flags,
+// which are applied on top of programmatic and file configuration.
+type overrides struct {
+	level  *Level
+	levels map[string]Level
+	format string
+	layout string
+	file   string
+	writer Writer
+}
+
+// newEnvOverrides creates overrides from environment variables.
+func newEnvOverrides() *overrides {
+	o := &overrides{
+		levels: make(map[string]Level),
+		format: os.Getenv(FormatEnvKey),
+		layout: os.Getenv(LayoutEnvKey),
+		file:   os.Getenv(FileEnvKey),
+	}
+
+	for _, env := range os.Environ() {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if kv[0] != LevelEnvKey && !strings.HasPrefix(kv[0], LevelEnvKey+"_") {
+			continue
+		}
+
+		lvl, err := ParseLevelE(kv[1])
+		if err != nil {
+			defaultStatusManager.Error("environment", "invalid level in %v: %v", kv[0], err)
+			continue
+		}
+		if kv[0] == LevelEnvKey {
+			o.level = &lvl
+		} else if name := kv[0][len(LevelEnvKey)+1:]; len(name) != 0 {
+			o.levels[strings.Replace(name, "__", "/", -1)] = lvl
+		}
+	}
+	// the levels in list are applied after, so they take precedence
+	if list := os.Getenv(LoggerLevelsEnvKey); len(list) != 0 {
+		for _, item := range strings.Split(list, ",") {
+			name, lvl, err := parseLoggerLevel(strings.TrimSpace(item))
+			if err != nil {
+				defaultStatusManager.Error("environment", "invalid level in %v: %v",
+					LoggerLevelsEnvKey, err)
+				continue
+			}
+			o.levels[name] = lvl
+		}
+	}
+
+	if err := o.buildWriter(); err != nil {
+		defaultStatusManager.Error("environment", "invalid writer: %v", err)
+	}
+
+	return o
+}
+
+// buildWriter builds the writer for root logger if format, layout or file is set.
+func (o *overrides) buildWriter() error {
+	o.writer = nil
+	if len(o.format) == 0 && len(o.layout) == 0 && len(o.file) == 0 {
+		return nil
+	}
+
+	var encoder Encoder
+	var err error
+	format := strings.ToLower(o.format)
+	if len(format) == 0 && (len(o.layout) != 0 || len(o.file) == 0) {
+		format = "pattern"
+	}
+	switch format {
+	case "json":
+		encoder = NewJsonEncoder()
+	case "pattern":
+		encoder, err = NewPatternEncoderE(func(opt *PatternEncoderOption) {
+			opt.Layout = o.layout
+		})
+		if err != nil {
+			return err
+		}
+	case "":
+		// the default encoder of file writer will be used
+	default:
+		return fmt.Errorf("unknown format: %v", o.format)
+	}
+
+	if len(o.file) == 0 {
+		o.writer = NewConsoleWriter(func(opt *ConsoleWriterOption) {
+			opt.Encoder = encoder
+		})
+	} else {
+		o.writer = NewFileWriter(func(opt *FileWriterOption) {
+			if encoder != nil {
+				opt.Encoder = encoder
+			}
+			opt.Filename = o.file
+		})
+	}
+
+	return nil
+}
+
+// parseLoggerLevel parses the level of named logger in the form of name=level.
+func parseLoggerLevel(v string) (string, Level, error) {
+	kv := strings.SplitN(v, "=", 2)
+	if len(kv) != 2 || len(kv[0]) == 0 {
+		return "", TraceLevel, fmt.Errorf("logger level should be name=level: %q", v)
+	}
+	lvl, err := ParseLevelE(kv[1])
+
+	return kv[0], lvl, err
+}
+
+// RegisterFlags registers the flags to configure the default logger context.
+func RegisterFlags(fs *flag.FlagSet) {
+	defaultContext.RegisterFlags(fs)
+}
+
+// RegisterFlags registers the flags to configure current logger context. The
+// flags are the same as environment variables, and will override them:
+//
+//	-slago.level info
+//	-slago.logger-level github.com/acme/db=debug (can be repeated)
+//	-slago.format json|pattern
+//	-slago.layout "#level #message"
+//	-slago.file /var/log/app.log
+func (lc *LoggerContext) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var(&overrideFlag{lc: lc, set: func(o *overrides, v string) error {
+		lvl, err := ParseLevelE(v)
+		o.level = &lvl
+		return err
+	}}, "slago.level", "level of root logger")
+	fs.Var(&overrideFlag{lc: lc, set: func(o *overrides, v string) error {
+		name, lvl, err := parseLoggerLevel(v)
+		o.levels[name] = lvl
+		return err
+	}}, "slago.logger-level", "level of named logger as name=level, can be repeated")
+	fs.Var(&overrideFlag{lc: lc, writer: true, set: func(o *overrides, v string) error {
+		o.format = v
+		return nil
+	}}, "slago.format", "format of root writer, json or pattern")
+	fs.Var(&overrideFlag{lc: lc, writer: true, set: func(o *overrides, v string) error {
+		o.layout = v
+		return nil
+	}}, "slago.layout", "layout of pattern format")
+	fs.Var(&overrideFlag{lc: lc, writer: true, set: func(o *overrides, v string) error {
+		o.file = v
+		return nil
+	}}, "slago.file", "file of root writer, console will be used if not set")
+}
+
+// overrideFlag is a flag value which overrides the settings of logger context.
+type overrideFlag struct {
+	lc     *LoggerContext
+	value  string
+	writer bool
+	set    func(o *overrides, v string) error
+}
+
+func (f *overrideFlag) String() string {
+	return f.value
+}
+
+func (f *overrideFlag) Set(v string) error {
+	f.value = v
+	return f.lc.updateOverrides(func(o *overrides) error {
+		if err := f.set(o, v); err != nil {
+			return err
+		}
+		if f.writer {
+			return o.buildWriter()
+		}
+
+		return nil
+	})
+}
+
+// updateOverrides updates the overrides and applies them.
+func (lc *LoggerContext) updateOverrides(update func(o *overrides) error) error {
+	lc.locker.Lock()
+	if lc.overrides == nil {
+		lc.overrides = newEnvOverrides()
+	}
+	// the overrides are copied, so it won't be changed if any error occurs
+	o := *lc.overrides
+	o.levels = make(map[string]Level)
+	for k, v := range lc.overrides.levels {
+		o.levels[k] = v
+	}
+	if err := update(&o); err != nil {
+		lc.locker.Unlock()
+		return err
+	}
+	old := lc.overrides.writer
+	lc.overrides = &o
+	lc.locker.Unlock()
+
+	lc.applyOverrides()
+	if old != nil && old != o.writer {
+		if l, ok := old.(Lifecycle); ok {
+			l.Stop()
+		}
+	}
+
+	return nil
+}
+
+// applyOverrides applies the levels and writer of overrides to the loggers.
+// It takes no effect before the logger context is initialized, and the
+// overrides will be applied when initializing.
+func (lc *LoggerContext) applyOverrides() {
+	lc.locker.Lock()
+	if lc.cache == nil {
+		lc.locker.Unlock()
+		return
+	}
+	root, writer := lc.applyOverrideLevels()
+	lc.locker.Unlock()
+
+	if writer != nil {
+		root.fixWriters(writer)
+	}
+}
+
+// applyOverrideLevels applies the levels of overrides, and returns the root
+// logger with writer to override. It should be called with locker held.
+func (lc *LoggerContext) applyOverrideLevels() (*classicLogger, Writer) {
+	if lc.overrides == nil {
+		lc.overrides = newEnvOverrides()
+	}
+
+	// the levels of overrides are fixed, so they won't be changed by
+	// programmatic or file configuration
+	for _, logger := range lc.cache {
+		logger.unfixLevel()
+	}
+	root := lc.cache[RootLoggerName]
+	if lc.overrides.level != nil {
+		root.fixLevel(*lc.overrides.level)
+	}
+	names := make([]string, 0, len(lc.overrides.levels))
+	for name := range lc.overrides.levels {
+		names = append(names, name)
+	}
+	// parent loggers are set first
+	sort.Strings(names)
+	for _, name := range names {
+		lc.findLogger(name).fixLevel(lc.overrides.levels[name])
+	}
+
+	return root, lc.overrides.writer
+}
+
+// overrideWriter gets the writer to override root writers, nil if not set.
+func (lc *LoggerContext) overrideWriter() Writer {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	if lc.overrides == nil {
+		return nil
+	}
+
+	return lc.overrides.writer
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEnv(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "env test")
+}
+
+var _ = Describe("environment and flags", func() {
+	It("levels from environment", func() {
+		_ = os.Setenv(LevelEnvKey, "warn")
+		_ = os.Setenv(LevelEnvKey+"_acme/db", "debug")
+		defer os.Unsetenv(LevelEnvKey)
+		defer os.Unsetenv(LevelEnvKey + "_acme/db")
+
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(WarnLevel))
+		Expect(lc.EffectiveLevel("acme")).To(Equal(WarnLevel))
+		Expect(lc.EffectiveLevel("acme/db/pool")).To(Equal(DebugLevel))
+
+		// environment overrides configuration
+		config, _ := ParseConfiguration([]byte(`{"root":{"level":"error"}}`))
+		Expect(lc.Configure(config)).To(BeNil())
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(WarnLevel))
+
+		// environment overrides levels set programmatically
+		lc.Logger().SetLevel(ErrorLevel)
+		lc.Logger("acme/db").SetLevel(ErrorLevel)
+		lc.ResetLevel("acme/db")
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(WarnLevel))
+		Expect(lc.EffectiveLevel("acme/db")).To(Equal(DebugLevel))
+		lc.Logger("acme").SetLevel(ErrorLevel)
+		Expect(lc.EffectiveLevel("acme")).To(Equal(ErrorLevel))
+
+		rec := httptest.NewRecorder()
+		lc.LevelHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/",
+			strings.NewReader(`{"name":"acme/db","level":"info"}`)))
+		Expect(rec.Code).To(Equal(http.StatusConflict))
+		Expect(lc.EffectiveLevel("acme/db")).To(Equal(DebugLevel))
+	})
+	It("shell safe levels from environment", func() {
+		_ = os.Setenv(LevelEnvKey+"_acme__cache", "error")
+		_ = os.Setenv(LoggerLevelsEnvKey, "github.com/acme/db=warn, acme/queue=info")
+		_ = os.Setenv(LevelEnvKey+"S", "unknown")
+		defer os.Unsetenv(LevelEnvKey + "_acme__cache")
+		defer os.Unsetenv(LoggerLevelsEnvKey)
+		defer os.Unsetenv(LevelEnvKey + "S")
+
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		Expect(lc.EffectiveLevel("acme/cache")).To(Equal(ErrorLevel))
+		Expect(lc.EffectiveLevel("github.com/acme/db")).To(Equal(WarnLevel))
+		Expect(lc.EffectiveLevel("acme/queue")).To(Equal(InfoLevel))
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(TraceLevel))
+	})
+	It("flags", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		lc.RegisterFlags(fs)
+		Expect(fs.Parse([]string{"-slago.level", "info",
+			"-slago.logger-level", "acme=error", "-slago.format", "json"})).To(BeNil())
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(InfoLevel))
+		Expect(lc.EffectiveLevel("acme/db")).To(Equal(ErrorLevel))
+
+		root := lc.Logger().(*classicLogger)
+		Expect(root.output.writer.writers).To(HaveLen(1))
+		Expect(root.output.writer.writers[0].Encoder()).To(BeAssignableToTypeOf(NewJsonEncoder()))
+		root.AddWriter(&bufferWriter{&bytes.Buffer{}})
+		Expect(root.output.writer.writers).To(HaveLen(1))
+
+		Expect(fs.Parse([]string{"-slago.level", "unknown"})).NotTo(BeNil())
+		Expect(fs.Parse([]string{"-slago.format", "xml"})).NotTo(BeNil())
+		Expect(lc.EffectiveLevel(RootLoggerName)).To(Equal(InfoLevel))
+	})
+})
@@ -0,0 +1,112 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	errorMessageKey = "message"
+	errorTypeKey    = "type"
+	errorCausesKey  = "causes"
+
+	maxErrorDepth = 16
+)
+
+// ErrorWithFields represents an error which exposes extra fields to log.
+type ErrorWithFields interface {
+	error
+
+	// SlagoFields gets the fields of this error.
+	SlagoFields() map[string]interface{}
+}
+
+// ErrorObject creates an ObjectMarshaler which encodes error as a structured
+// object with message, type, fields and the wrapped causes. Both the errors
+// implement Unwrap() error and Unwrap() []error are supported.
+func ErrorObject(err error) ObjectMarshaler {
+	return &errorObject{
+		err: err,
+	}
+}
+
+type errorObject struct {
+	err   error
+	depth int
+}
+
+func (o *errorObject) MarshalSlagoObject(enc ObjectEncoder) error {
+	enc.Str(errorMessageKey, o.err.Error())
+	enc.Str(errorTypeKey, fmt.Sprintf("%T", o.err))
+
+	if ef, ok := o.err.(ErrorWithFields); ok {
+		fields := ef.SlagoFields()
+		// sort the keys to keep the output stable
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			switch k {
+			case errorMessageKey, errorTypeKey, errorCausesKey:
+				// reserved keys, skip
+			default:
+				enc.Interface(k, fields[k])
+			}
+		}
+	}
+
+	causes := unwrapErrors(o.err)
+	if len(causes) == 0 || o.depth >= maxErrorDepth {
+		return nil
+	}
+
+	enc.Array(errorCausesKey, ArrayMarshalerFunc(func(arr ArrayEncoder) error {
+		for _, cause := range causes {
+			arr.AppendObject(&errorObject{
+				err:   cause,
+				depth: o.depth + 1,
+			})
+		}
+		return nil
+	}))
+
+	return nil
+}
+
+// unwrapErrors gets the errors wrapped by given error.
+func unwrapErrors(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		var causes []error
+		for _, cause := range e.Unwrap() {
+			if cause != nil {
+				causes = append(causes, cause)
+			}
+		}
+		return causes
+
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+
+	return nil
+}
@@ -27,7 +27,10 @@ type LogEvent struct {
 	level       *bytes.Buffer
 	logger      *bytes.Buffer
 	caller      *bytes.Buffer
+	stack       *bytes.Buffer
+	markers     *bytes.Buffer
 	message     *bytes.Buffer
+	template    *bytes.Buffer
 	fields      *bytes.Buffer
 	fieldsIndex *bytes.Buffer
 }
@@ -40,7 +43,10 @@ var (
 				rfc3339Nano: new(bytes.Buffer),
 				logger:      new(bytes.Buffer),
 				caller:      new(bytes.Buffer),
+				stack:       new(bytes.Buffer),
+				markers:     new(bytes.Buffer),
 				message:     new(bytes.Buffer),
+				template:    new(bytes.Buffer),
 				fields:      new(bytes.Buffer),
 				fieldsIndex: new(bytes.Buffer),
 			}
@@ -55,7 +61,16 @@ func (e *LogEvent) Time() []byte {
 
 // LevelInt returns level int value.
 func (e *LogEvent) LevelInt() Level {
-	return ParseLevel(e.level.String())
+	lvl := e.level.String()
+	if level, err := ParseLevelE(lvl); err == nil {
+		return level
+	}
+	// the level which is not registered is written as number
+	if n, err := strconv.ParseInt(lvl, 10, 8); err == nil {
+		return Level(n)
+	}
+
+	return TraceLevel
 }
 
 // Level returns level string bytes.
@@ -68,11 +83,76 @@ func (e *LogEvent) Logger() []byte {
 	return e.logger.Bytes()
 }
 
+// Caller returns caller bytes formatted as function(file:line).
+func (e *LogEvent) Caller() []byte {
+	return e.caller.Bytes()
+}
+
+// Stack gets each frame of stack, the frame is formatted as function(file:line).
+func (e *LogEvent) Stack(callback func(frame []byte) error) error {
+	if e.stack.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	var buf []byte
+	_, _ = jsonparser.ArrayEach(e.stack.Bytes(), func(v []byte,
+		_ jsonparser.ValueType, _ int, _ error) {
+		if err != nil {
+			return
+		}
+		buf, _ = jsonparser.Unescape(v, buf[:0])
+		err = callback(buf)
+	})
+
+	return err
+}
+
+// Markers gets each marker of this event.
+func (e *LogEvent) Markers(callback func(marker []byte) error) error {
+	if e.markers.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	var buf []byte
+	_, _ = jsonparser.ArrayEach(e.markers.Bytes(), func(v []byte,
+		_ jsonparser.ValueType, _ int, _ error) {
+		if err != nil {
+			return
+		}
+		buf, _ = jsonparser.Unescape(v, buf[:0])
+		err = callback(buf)
+	})
+
+	return err
+}
+
+// HasMarker checks if this event has any of the given markers.
+func (e *LogEvent) HasMarker(names ...string) bool {
+	err := e.Markers(func(marker []byte) error {
+		for _, name := range names {
+			if string(marker) == name {
+				return errFound
+			}
+		}
+		return nil
+	})
+
+	return err == errFound
+}
+
 // Message returns message bytes.
 func (e *LogEvent) Message() []byte {
 	return e.message.Bytes()
 }
 
+// MessageTemplate returns template bytes of message logged with Msgt, the
+// bytes are kept escaped as in json string.
+func (e *LogEvent) MessageTemplate() []byte {
+	return e.template.Bytes()
+}
+
 // Fields gets extra key and value bytes.
 func (e *LogEvent) Fields(callback func(k, v []byte, isString bool) error) error {
 	var kvIndex = 0
@@ -108,6 +188,34 @@ func (e *LogEvent) Fields(callback func(k, v []byte, isString bool) error) error
 	return nil
 }
 
+// FlatFields gets extra key and value bytes like Fields, but nested objects
+// will be flattened and their keys are joined with dot, e.g. user.name.
+func (e *LogEvent) FlatFields(callback func(k, v []byte, isString bool) error) error {
+	return e.Fields(func(k, v []byte, isString bool) error {
+		return flattenField(nil, k, v, isString, callback)
+	})
+}
+
+func flattenField(prefix, k, v []byte, isString bool,
+	callback func(k, v []byte, isString bool) error) error {
+	key := k
+	if len(prefix) != 0 {
+		key = make([]byte, 0, len(prefix)+len(k)+1)
+		key = append(key, prefix...)
+		key = append(key, '.')
+		key = append(key, k...)
+	}
+
+	if isString || len(v) == 0 || v[0] != '{' {
+		return callback(key, v, isString)
+	}
+
+	return jsonparser.ObjectEach(v, func(nk []byte, nv []byte,
+		dataType jsonparser.ValueType, _ int) error {
+		return flattenField(key, nk, nv, dataType == jsonparser.String, callback)
+	})
+}
+
 func makeEvent(p []byte) *LogEvent {
 	event := eventPool.Get().(*LogEvent)
 	_ = jsonparser.ObjectEach(p, func(k []byte, v []byte,
@@ -116,9 +224,22 @@ func makeEvent(p []byte) *LogEvent {
 		case TimestampFieldKey:
 			event.rfc3339Nano.Write(v)
 		case LevelFieldKey:
+			// the last one wins, since custom level name may be added as field
+			event.level.Reset()
 			event.level.Write(v)
 		case LoggerFieldKey:
 			event.logger.Write(v)
+		case CallerFieldKey:
+			event.caller.Grow(len(v))
+			temp := event.caller.Bytes()
+			c, _ := jsonparser.Unescape(v, temp)
+			event.caller.Write(c)
+		case StackFieldKey:
+			event.stack.Write(v)
+		case MarkerFieldKey:
+			event.appendMarkers(v, dataType)
+		case MessageTemplateFieldKey:
+			event.template.Write(v)
 		case MessageFieldKey:
 			event.message.Grow(len(v))
 			temp := event.message.Bytes()
@@ -146,12 +267,37 @@ func makeEvent(p []byte) *LogEvent {
 	return event
 }
 
+// appendMarkers merges markers into one json array, since markers may be
+// added more than once.
+func (e *LogEvent) appendMarkers(v []byte, dataType jsonparser.ValueType) {
+	if dataType == jsonparser.String {
+		e.appendMarkers(append(append([]byte(`["`), v...), `"]`...), jsonparser.Array)
+		return
+	}
+
+	if dataType != jsonparser.Array || len(bytes.TrimSpace(v[1:len(v)-1])) == 0 {
+		return
+	}
+
+	if e.markers.Len() == 0 {
+		e.markers.Write(v)
+		return
+	}
+
+	e.markers.Truncate(e.markers.Len() - 1)
+	e.markers.WriteByte(',')
+	e.markers.Write(v[1:])
+}
+
 func (e *LogEvent) recycle() {
 	e.rfc3339Nano.Reset()
 	e.level.Reset()
 	e.logger.Reset()
 	e.caller.Reset()
+	e.stack.Reset()
+	e.markers.Reset()
 	e.message.Reset()
+	e.template.Reset()
 	e.fields.Reset()
 	e.fieldsIndex.Reset()
 	eventPool.Put(e)
@@ -0,0 +1,144 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// AppendAny adds value into record with the typed method matching its type,
+// and falls back to Interface. This is used by slago logger implementations.
+func AppendAny(r Record, key string, val interface{}) Record {
+	switch v := val.(type) {
+	case nil:
+		return r.Interface(key, nil)
+	case string:
+		return r.Str(key, v)
+	case []string:
+		return r.Strs(key, v)
+	case []byte:
+		return r.Bytes(key, v)
+	case json.RawMessage:
+		return r.RawJSON(key, v)
+	case error:
+		return r.Object(key, ErrorObject(v))
+	case []error:
+		return r.Errs(key, v)
+	case bool:
+		return r.Bool(key, v)
+	case []bool:
+		return r.Bools(key, v)
+	case int:
+		return r.Int(key, v)
+	case []int:
+		return r.Ints(key, v)
+	case int8:
+		return r.Int8(key, v)
+	case []int8:
+		return r.Ints8(key, v)
+	case int16:
+		return r.Int16(key, v)
+	case []int16:
+		return r.Ints16(key, v)
+	case int32:
+		return r.Int32(key, v)
+	case []int32:
+		return r.Ints32(key, v)
+	case int64:
+		return r.Int64(key, v)
+	case []int64:
+		return r.Ints64(key, v)
+	case uint:
+		return r.Uint(key, v)
+	case []uint:
+		return r.Uints(key, v)
+	case uint8:
+		return r.Uint8(key, v)
+	case uint16:
+		return r.Uint16(key, v)
+	case []uint16:
+		return r.Uints16(key, v)
+	case uint32:
+		return r.Uint32(key, v)
+	case []uint32:
+		return r.Uints32(key, v)
+	case uint64:
+		return r.Uint64(key, v)
+	case []uint64:
+		return r.Uints64(key, v)
+	case float32:
+		return r.Float32(key, v)
+	case []float32:
+		return r.Floats32(key, v)
+	case float64:
+		return r.Float64(key, v)
+	case []float64:
+		return r.Floats64(key, v)
+	case time.Time:
+		return r.Time(key, v)
+	case []time.Time:
+		return r.Times(key, v)
+	case time.Duration:
+		return r.Dur(key, v)
+	case []time.Duration:
+		return r.Durs(key, v)
+	case net.IP:
+		return r.IPAddr(key, v)
+	case net.IPNet:
+		return r.IPPrefix(key, v)
+	case *net.IPNet:
+		return r.IPPrefix(key, *v)
+	case net.HardwareAddr:
+		return r.MACAddr(key, v)
+	case url.URL:
+		return r.URL(key, &v)
+	case *url.URL:
+		return r.URL(key, v)
+	case map[string]interface{}:
+		return r.Dict(key, func(r Record) {
+			r.Fields(v)
+		})
+	case ObjectMarshaler:
+		return r.Object(key, v)
+	case ArrayMarshaler:
+		return r.Array(key, v)
+	case fmt.Stringer:
+		return r.Stringer(key, v)
+	default:
+		return r.Interface(key, val)
+	}
+}
+
+// AppendFields adds all the fields in map into record with sorted keys.
+// This is used by slago logger implementations.
+func AppendFields(r Record, fields map[string]interface{}) Record {
+	// sort the keys to keep the output stable
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		r.Any(k, fields[k])
+	}
+
+	return r
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// FieldContext represents a set of fields which will be bound to a child logger.
+// All the fields added into context will be appended to every record the
+// child logger creates.
+type FieldContext struct {
+	record    Record
+	newLogger func() SlaLogger
+}
+
+// NewFieldContext creates a new instance of field context. The record is used
+// to hold the fields, and newLogger will be called to create the child logger.
+func NewFieldContext(r Record, newLogger func() SlaLogger) *FieldContext {
+	return &FieldContext{
+		record:    r,
+		newLogger: newLogger,
+	}
+}
+
+// Logger creates a child logger with all the fields in current context.
+func (c *FieldContext) Logger() SlaLogger {
+	return c.newLogger()
+}
+
+// Str adds string value to this context.
+func (c *FieldContext) Str(key, val string) *FieldContext {
+	c.record.Str(key, val)
+	return c
+}
+
+// Strs adds string array value to this context.
+func (c *FieldContext) Strs(key string, val []string) *FieldContext {
+	c.record.Strs(key, val)
+	return c
+}
+
+// Bytes adds byte array value to this context.
+func (c *FieldContext) Bytes(key string, val []byte) *FieldContext {
+	c.record.Bytes(key, val)
+	return c
+}
+
+// Hex adds hex byte array value to this context.
+func (c *FieldContext) Hex(key string, val []byte) *FieldContext {
+	c.record.Hex(key, val)
+	return c
+}
+
+// Err adds err to this context.
+func (c *FieldContext) Err(err error) *FieldContext {
+	c.record.Err(err)
+	return c
+}
+
+// Errs adds err array to this context.
+func (c *FieldContext) Errs(key string, errs []error) *FieldContext {
+	c.record.Errs(key, errs)
+	return c
+}
+
+// Bool adds bool value to this context.
+func (c *FieldContext) Bool(key string, val bool) *FieldContext {
+	c.record.Bool(key, val)
+	return c
+}
+
+// Bools adds bool array value to this context.
+func (c *FieldContext) Bools(key string, val []bool) *FieldContext {
+	c.record.Bools(key, val)
+	return c
+}
+
+// Int adds int value to this context.
+func (c *FieldContext) Int(key string, val int) *FieldContext {
+	c.record.Int(key, val)
+	return c
+}
+
+// Ints adds int array value to this context.
+func (c *FieldContext) Ints(key string, val []int) *FieldContext {
+	c.record.Ints(key, val)
+	return c
+}
+
+// Int8 adds int8 value to this context.
+func (c *FieldContext) Int8(key string, val int8) *FieldContext {
+	c.record.Int8(key, val)
+	return c
+}
+
+// Ints8 adds int8 array value to this context.
+func (c *FieldContext) Ints8(key string, val []int8) *FieldContext {
+	c.record.Ints8(key, val)
+	return c
+}
+
+// Int16 adds int16 value to this context.
+func (c *FieldContext) Int16(key string, val int16) *FieldContext {
+	c.record.Int16(key, val)
+	return c
+}
+
+// Ints16 adds int16 array value to this context.
+func (c *FieldContext) Ints16(key string, val []int16) *FieldContext {
+	c.record.Ints16(key, val)
+	return c
+}
+
+// Int32 adds int32 value to this context.
+func (c *FieldContext) Int32(key string, val int32) *FieldContext {
+	c.record.Int32(key, val)
+	return c
+}
+
+// Ints32 adds int32 array value to this context.
+func (c *FieldContext) Ints32(key string, val []int32) *FieldContext {
+	c.record.Ints32(key, val)
+	return c
+}
+
+// Int64 adds int64 value to this context.
+func (c *FieldContext) Int64(key string, val int64) *FieldContext {
+	c.record.Int64(key, val)
+	return c
+}
+
+// Ints64 adds int64 array value to this context.
+func (c *FieldContext) Ints64(key string, val []int64) *FieldContext {
+	c.record.Ints64(key, val)
+	return c
+}
+
+// Uint adds uint value to this context.
+func (c *FieldContext) Uint(key string, val uint) *FieldContext {
+	c.record.Uint(key, val)
+	return c
+}
+
+// Uints adds uint array value to this context.
+func (c *FieldContext) Uints(key string, val []uint) *FieldContext {
+	c.record.Uints(key, val)
+	return c
+}
+
+// Uint8 adds uint8 value to this context.
+func (c *FieldContext) Uint8(key string, val uint8) *FieldContext {
+	c.record.Uint8(key, val)
+	return c
+}
+
+// Uints8 adds uint8 array value to this context.
+func (c *FieldContext) Uints8(key string, val []uint8) *FieldContext {
+	c.record.Uints8(key, val)
+	return c
+}
+
+// Uint16 adds uint16 value to this context.
+func (c *FieldContext) Uint16(key string, val uint16) *FieldContext {
+	c.record.Uint16(key, val)
+	return c
+}
+
+// Uints16 adds uint16 array value to this context.
+func (c *FieldContext) Uints16(key string, val []uint16) *FieldContext {
+	c.record.Uints16(key, val)
+	return c
+}
+
+// Uint32 adds uint32 value to this context.
+func (c *FieldContext) Uint32(key string, val uint32) *FieldContext {
+	c.record.Uint32(key, val)
+	return c
+}
+
+// Uints32 adds uint32 array value to this context.
+func (c *FieldContext) Uints32(key string, val []uint32) *FieldContext {
+	c.record.Uints32(key, val)
+	return c
+}
+
+// Uint64 adds uint64 value to this context.
+func (c *FieldContext) Uint64(key string, val uint64) *FieldContext {
+	c.record.Uint64(key, val)
+	return c
+}
+
+// Uints64 adds uint64 array value to this context.
+func (c *FieldContext) Uints64(key string, val []uint64) *FieldContext {
+	c.record.Uints64(key, val)
+	return c
+}
+
+// Float32 adds float32 value to this context.
+func (c *FieldContext) Float32(key string, val float32) *FieldContext {
+	c.record.Float32(key, val)
+	return c
+}
+
+// Floats32 adds float32 array value to this context.
+func (c *FieldContext) Floats32(key string, val []float32) *FieldContext {
+	c.record.Floats32(key, val)
+	return c
+}
+
+// Float64 adds float64 value to this context.
+func (c *FieldContext) Float64(key string, val float64) *FieldContext {
+	c.record.Float64(key, val)
+	return c
+}
+
+// Floats64 adds float64 array value to this context.
+func (c *FieldContext) Floats64(key string, val []float64) *FieldContext {
+	c.record.Floats64(key, val)
+	return c
+}
+
+// Time adds time value to this context.
+func (c *FieldContext) Time(key string, val time.Time) *FieldContext {
+	c.record.Time(key, val)
+	return c
+}
+
+// Times adds time array value to this context.
+func (c *FieldContext) Times(key string, val []time.Time) *FieldContext {
+	c.record.Times(key, val)
+	return c
+}
+
+// Dur adds duration value to this context.
+func (c *FieldContext) Dur(key string, val time.Duration) *FieldContext {
+	c.record.Dur(key, val)
+	return c
+}
+
+// Durs adds duration array value to this context.
+func (c *FieldContext) Durs(key string, val []time.Duration) *FieldContext {
+	c.record.Durs(key, val)
+	return c
+}
+
+// RawJSON adds pre-encoded json to this context.
+func (c *FieldContext) RawJSON(key string, val []byte) *FieldContext {
+	c.record.RawJSON(key, val)
+	return c
+}
+
+// Stringer adds value of fmt.Stringer to this context.
+func (c *FieldContext) Stringer(key string, val fmt.Stringer) *FieldContext {
+	c.record.Stringer(key, val)
+	return c
+}
+
+// IPAddr adds IP address to this context.
+func (c *FieldContext) IPAddr(key string, ip net.IP) *FieldContext {
+	c.record.IPAddr(key, ip)
+	return c
+}
+
+// IPPrefix adds IP prefix (CIDR) to this context.
+func (c *FieldContext) IPPrefix(key string, pfx net.IPNet) *FieldContext {
+	c.record.IPPrefix(key, pfx)
+	return c
+}
+
+// MACAddr adds hardware address to this context.
+func (c *FieldContext) MACAddr(key string, ha net.HardwareAddr) *FieldContext {
+	c.record.MACAddr(key, ha)
+	return c
+}
+
+// URL adds url to this context.
+func (c *FieldContext) URL(key string, val *url.URL) *FieldContext {
+	c.record.URL(key, val)
+	return c
+}
+
+// Dict adds a nested object built by the given function to this context.
+func (c *FieldContext) Dict(key string, f func(r Record)) *FieldContext {
+	c.record.Dict(key, f)
+	return c
+}
+
+// Object adds a nested object encoded by ObjectMarshaler to this context.
+func (c *FieldContext) Object(key string, obj ObjectMarshaler) *FieldContext {
+	c.record.Object(key, obj)
+	return c
+}
+
+// Array adds an array encoded by ArrayMarshaler to this context.
+func (c *FieldContext) Array(key string, arr ArrayMarshaler) *FieldContext {
+	c.record.Array(key, arr)
+	return c
+}
+
+// Interface adds interface value to this context.
+func (c *FieldContext) Interface(key string, val interface{}) *FieldContext {
+	c.record.Interface(key, val)
+	return c
+}
+
+// Any adds value to this context with the typed method matching its type.
+func (c *FieldContext) Any(key string, val interface{}) *FieldContext {
+	c.record.Any(key, val)
+	return c
+}
+
+// Fields adds all the fields in map to this context.
+func (c *FieldContext) Fields(fields map[string]interface{}) *FieldContext {
+	c.record.Fields(fields)
+	return c
+}
+
+// Func adds value returned by the given function to this context.
+func (c *FieldContext) Func(key string, f func() interface{}) *FieldContext {
+	c.record.Func(key, f)
+	return c
+}
+
+// Lazy adds fields with the given function to this context.
+func (c *FieldContext) Lazy(f func(r Record)) *FieldContext {
+	c.record.Lazy(f)
+	return c
+}
+
+// Marker adds markers to this context.
+func (c *FieldContext) Marker(names ...string) *FieldContext {
+	c.record.Marker(names...)
+	return c
+}
+
+// Ctx adds fields extracted from context to this context.
+func (c *FieldContext) Ctx(ctx context.Context) *FieldContext {
+	c.record.Ctx(ctx)
+	return c
+}
@@ -63,14 +63,25 @@ func NewFileWriter(options ...func(*FileWriterOption)) Writer {
 	return fw
 }
 
-func (fw *fileWriter) Start() {
+func (fw *fileWriter) Start() error {
+	fw.locker.Lock()
+	defer fw.locker.Unlock()
+
+	// the writer may be shared by multiple loggers
+	if fw.file != nil {
+		return nil
+	}
+
 	if err := fw.openExistingOrNew(); err != nil {
-		ReportfExit("file writer start error: %v", err)
+		return fmt.Errorf("file writer start error: %v", err)
 	}
 
 	if err := fw.opts.RollingPolicy.Prepare(); err != nil {
-		ReportfExit("start rolling policy error: %v\n", err)
+		_ = fw.close()
+		return fmt.Errorf("start rolling policy error: %v", err)
 	}
+
+	return nil
 }
 
 func (fw *fileWriter) Stop() {
@@ -124,7 +135,11 @@ func (fw *fileWriter) close() error {
 	if fw.file == nil {
 		return nil
 	}
-	err := fw.file.Close()
+	// flush the data in file system cache before closing
+	err := fw.file.Sync()
+	if e := fw.file.Close(); err == nil {
+		err = e
+	}
 	fw.file = nil
 	return err
 }
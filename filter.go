@@ -42,6 +42,35 @@ func (f *levelFilter) Do(e *LogEvent) bool {
 	return f.level > e.LevelInt()
 }
 
+// markerFilter represents a filter by markers.
+type markerFilter struct {
+	markers []string
+	accept  bool
+}
+
+// NewMarkerFilter creates a new instance of markerFilter which filters
+// the events with any of the given markers.
+func NewMarkerFilter(markers ...string) Filter {
+	return &markerFilter{
+		markers: markers,
+	}
+}
+
+// NewMarkerAcceptFilter creates a new instance of markerFilter which only
+// accepts the events with any of the given markers. This is useful to
+// route marked events into a dedicated writer.
+func NewMarkerAcceptFilter(markers ...string) Filter {
+	return &markerFilter{
+		markers: markers,
+		accept:  true,
+	}
+}
+
+// Do will execute the filter.
+func (f *markerFilter) Do(e *LogEvent) bool {
+	return e.HasMarker(f.markers...) != f.accept
+}
+
 // keywordFilter represents a filter by key word rule.
 type keywordFilter struct {
 	keywords []string
@@ -57,7 +86,7 @@ func NewKeywordFilter(keywords ...string) Filter {
 var errFound = errors.New("found")
 
 func (f *keywordFilter) Do(e *LogEvent) bool {
-	err := e.Fields(func(k, v []byte, _ bool) error {
+	err := e.FlatFields(func(k, v []byte, _ bool) error {
 		if f.compare(k, v) {
 			return errFound
 		}
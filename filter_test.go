@@ -51,4 +51,24 @@ var _ = Describe("keyword filter", func() {
 		result := filter.Do(event)
 		Expect(result).To(Equal(true))
 	})
+	It("filter nested field", func() {
+		nested := makeEvent([]byte(`{"level":"INFO","user":{"name":"bob"}}`))
+		Expect(NewKeywordFilter("user.name=bob").Do(nested)).To(Equal(true))
+		Expect(NewKeywordFilter("user.name=alice").Do(nested)).To(Equal(false))
+	})
+})
+
+var _ = Describe("marker filter", func() {
+	var event = makeEvent([]byte(`{"level":"INFO","marker":["AUDIT"]}`))
+	var plain = makeEvent([]byte(`{"level":"INFO","int":88}`))
+	It("filter marked", func() {
+		filter := NewMarkerFilter("AUDIT", "SECURITY")
+		Expect(filter.Do(event)).To(Equal(true))
+		Expect(filter.Do(plain)).To(Equal(false))
+	})
+	It("accept marked", func() {
+		filter := NewMarkerAcceptFilter("AUDIT")
+		Expect(filter.Do(event)).To(Equal(false))
+		Expect(filter.Do(plain)).To(Equal(true))
+	})
 })
@@ -16,7 +16,6 @@ package slago
 
 import (
 	"bytes"
-	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -32,6 +31,10 @@ const (
 	TimestampFieldKey = "time"
 	MessageFieldKey   = "message"
 	LoggerFieldKey    = "logger_name"
+	CallerFieldKey    = "caller"
+	StackFieldKey     = "stack"
+	ErrorFieldKey     = "error"
+	MarkerFieldKey    = "marker"
 
 	TimestampFormat = time.RFC3339Nano
 
@@ -44,72 +47,60 @@ const (
 
 // BrigeWrite writes data from bridge to slago logger.
 func BrigeWrite(bridge Bridge, p []byte) error {
+	return bridgeWrite(defaultContext, bridge, p)
+}
+
+// bridgeWrite writes data from bridge to the root logger of given context.
+func bridgeWrite(lc *LoggerContext, bridge Bridge, p []byte) error {
 	lvl, _ := jsonparser.GetString(p, LevelFieldKey)
 	msg, _ := jsonparser.GetString(p, MessageFieldKey)
 
-	record := makeRecord(bridge.ParseLevel(lvl))
+	record := lc.Logger().Level(bridge.ParseLevel(lvl))
 	_ = jsonparser.ObjectEach(p, func(key []byte, value []byte,
 		dataType jsonparser.ValueType, _ int) error {
 		realKey := string(key)
 		switch realKey {
-		case LevelFieldKey:
-		case TimestampFieldKey:
+		case LevelFieldKey, MessageFieldKey:
 			// do nothing
 
-		case MessageFieldKey:
-			record.Msg(msg)
+		case TimestampFieldKey:
+			// keep the original time of bridged event
+			if t, err := time.Parse(TimestampFormat, string(value)); err == nil {
+				record.Timestamp(t)
+			}
 
 		default:
-			record.Bytes(realKey, value)
+			if dataType == jsonparser.String {
+				str, _ := jsonparser.ParseString(value)
+				record.Str(realKey, str)
+			} else {
+				// keep the native type of bridged field
+				record.RawJSON(realKey, value)
+			}
 		}
 
 		return nil
 	})
+	record.Msg(msg)
 
 	return nil
 }
 
-func makeRecord(lvl Level) Record {
-	switch lvl {
-	case DebugLevel:
-		return Logger().Debug()
-	case InfoLevel:
-		return Logger().Info()
-	case WarnLevel:
-		return Logger().Warn()
-	case ErrorLevel:
-		return Logger().Error()
-	case FatalLevel:
-		return Logger().Fatal()
-	case PanicLevel:
-		return Logger().Panic()
-	case TraceLevel:
-		fallthrough
-	default:
-		return Logger().Trace()
-	}
-}
-
-// Report reports message in stdou
+// Report reports message as an error status into default status manager.
 func Report(msg string) {
-	Reportf(msg)
+	Reportf("%s", msg)
 }
 
-// Reportf reports message with arguments in stdou
+// Reportf reports message with arguments as an error status into default status manager.
 func Reportf(format string, args ...interface{}) {
-	format = "slago: " + format
-	fmt.Println(colorize(colorRed, fmt.Sprintf(format, args...)))
+	defaultStatusManager.Error("slago", format, args...)
 }
 
-// ReportfExit reportes message with arguments in stdout and exit process.
+// ReportfExit reportes message with arguments as an error status into default
+// status manager and exit process with failure code.
 func ReportfExit(format string, args ...interface{}) {
 	Reportf(format, args...)
-	os.Exit(0)
-}
-
-// colorize adds ANSI color for given string.
-func colorize(color int, s string) string {
-	return fmt.Sprintf("\x1b[%dm%v\x1b[0m", color, s)
+	os.Exit(1)
 }
 
 // indexOfSlash gets the position of slash, starting at fromIndex.
@@ -247,13 +238,5 @@ func ReplaceJson(p []byte, buf *bytes.Buffer, searchKey string,
 // PackageName get the package name of caller.
 func PackageName(skip int) string {
 	pc, _, _, _ := runtime.Caller(skip + 1)
-	fn := runtime.FuncForPC(pc).Name()
-	index := 0
-	if i := strings.LastIndex(fn, "/"); i >= 0 {
-		index = i
-	}
-	if i := strings.Index(fn[index:], "."); i >= 0 {
-		index += i
-	}
-	return fn[:index]
+	return funcPackage(runtime.FuncForPC(pc).Name())
 }
@@ -17,6 +17,7 @@ package slago
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -38,4 +39,31 @@ var _ = Describe("helper", func() {
 		Expect(err).To(BeNil())
 		Expect(buf.String()).To(Equal(result))
 	})
+	It("bridge write keeps time", func() {
+		lc := NewLoggerContext()
+		defer lc.Reset()
+		buf := &bytes.Buffer{}
+		lc.Logger().AddWriter(&bufferWriter{buf})
+		bridge := &namedBridge{"bridge"}
+
+		// the logrus bridge uses RFC3339 format
+		_ = bridgeWrite(lc, bridge, []byte(
+			`{"level":"error","time":"2021-01-02T03:04:05+08:00","message":"rfc3339"}`))
+		Expect(buf.String()).To(ContainSubstring(`"time":"2021-01-02T03:04:05+08:00"`))
+
+		buf.Reset()
+		_ = bridgeWrite(lc, bridge, []byte(
+			`{"level":"error","time":"2021-01-02T03:04:05.123456789Z","message":"rfc3339nano"}`))
+		Expect(buf.String()).To(ContainSubstring(`"time":"2021-01-02T03:04:05.123456789Z"`))
+	})
+	It("record timestamp", func() {
+		lc := NewLoggerContext()
+		defer lc.Reset()
+		buf := &bytes.Buffer{}
+		lc.Logger().AddWriter(&bufferWriter{buf})
+
+		t := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+		lc.Logger().Error().Timestamp(t).Msg("timestamp")
+		Expect(buf.String()).To(ContainSubstring(`"time":"2021-01-02T03:04:05Z"`))
+	})
 })
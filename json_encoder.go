@@ -26,6 +26,7 @@ type jsonEncoder struct {
 	locker sync.Mutex
 	buf    *bytes.Buffer
 	tsBuf  *bytes.Buffer
+	strBuf []byte
 }
 
 // NewJsonEncoder creates a new instance of encoder to encode data to json.
@@ -56,7 +57,19 @@ func (je *jsonEncoder) Encode(e *LogEvent) ([]byte, error) {
 	je.writeKeyAndValue(TimestampFieldKey, timestamp, true)
 	je.writeKeyAndValue(LevelFieldKey, e.Level(), true)
 	je.writeKeyAndValue(LoggerFieldKey, e.Logger(), true)
-	je.writeKeyAndValue(MessageFieldKey, e.Message(), true)
+	je.writeKeyAndEscaped(MessageFieldKey, e.Message())
+	if template := e.MessageTemplate(); len(template) != 0 {
+		je.writeKeyAndValue(MessageTemplateFieldKey, template, true)
+	}
+	if caller := e.Caller(); len(caller) != 0 {
+		je.writeKeyAndEscaped(CallerFieldKey, caller)
+	}
+	if e.markers.Len() != 0 {
+		je.writeKeyAndValue(MarkerFieldKey, e.markers.Bytes(), false)
+	}
+	if e.stack.Len() != 0 {
+		je.writeKeyAndValue(StackFieldKey, e.stack.Bytes(), false)
+	}
 
 	_ = e.Fields(func(k, v []byte, isString bool) error {
 		je.writeKeyAndValue(string(k), v, isString)
@@ -87,3 +100,10 @@ func (je *jsonEncoder) writeKeyAndValue(key string, value []byte, isString bool)
 	}
 	je.buf.WriteByte(',')
 }
+
+// writeKeyAndEscaped writes the unescaped value as json string, such as
+// message and caller which may contain quotes or backslashes.
+func (je *jsonEncoder) writeKeyAndEscaped(key string, value []byte) {
+	je.strBuf = appendJsonString(je.strBuf[:0], string(value))
+	je.writeKeyAndValue(key, je.strBuf, false)
+}
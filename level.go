@@ -0,0 +1,247 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The builtin levels are spaced, so custom levels can be registered between them.
+const (
+	TraceLevel  Level = 0
+	DebugLevel  Level = 10
+	InfoLevel   Level = 20
+	NoticeLevel Level = 25
+	WarnLevel   Level = 30
+	ErrorLevel  Level = 40
+	FatalLevel  Level = 50
+	PanicLevel  Level = 60
+	// OffLevel is used to turn off logging with SetLevel.
+	OffLevel Level = math.MaxInt8
+)
+
+type Level int8
+
+type levelInfo struct {
+	name  string
+	color int
+}
+
+var (
+	levelLocker sync.RWMutex
+	levelNames  = map[string]Level{
+		"TRACE":  TraceLevel,
+		"DEBUG":  DebugLevel,
+		"INFO":   InfoLevel,
+		"NOTICE": NoticeLevel,
+		"WARN":   WarnLevel,
+		"ERROR":  ErrorLevel,
+		"FATAL":  FatalLevel,
+		"PANIC":  PanicLevel,
+		"OFF":    OffLevel,
+	}
+	levelInfos = map[Level]levelInfo{
+		TraceLevel:  {name: "TRACE", color: colorWhite},
+		DebugLevel:  {name: "DEBUG", color: colorBlue},
+		InfoLevel:   {name: "INFO", color: colorGreen},
+		NoticeLevel: {name: "NOTICE", color: colorCyan},
+		WarnLevel:   {name: "WARN", color: colorYellow},
+		ErrorLevel:  {name: "ERROR", color: colorRed},
+		FatalLevel:  {name: "FATAL", color: colorRed},
+		PanicLevel:  {name: "PANIC", color: colorRed},
+		OffLevel:    {name: "OFF", color: colorWhite},
+	}
+	levelAliases = map[string]string{
+		"WARNING":  "WARN",
+		"ERR":      "ERROR",
+		"CRITICAL": "FATAL",
+		"CRIT":     "FATAL",
+	}
+	// standard levels are supported by all the logging frameworks
+	standardLevels = []Level{
+		TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel, PanicLevel,
+	}
+)
+
+// RegisterLevel registers a custom level with name, severity and color. The
+// color is used by #color(#level), and it should be one of the colors supported
+// in color pattern. The name will be converted to upper case.
+func RegisterLevel(lvl Level, name string, color string) error {
+	name = strings.ToUpper(name)
+	if len(name) == 0 {
+		return fmt.Errorf("level name is empty")
+	}
+	if lvl >= OffLevel {
+		return fmt.Errorf("level %d should be lower than off level", lvl)
+	}
+	c, ok := colorMap[color]
+	if !ok {
+		return fmt.Errorf("unknown color %v for level %v", color, name)
+	}
+
+	levelLocker.Lock()
+	defer levelLocker.Unlock()
+
+	if _, ok := levelNames[name]; ok {
+		return fmt.Errorf("level name %v has been registered", name)
+	}
+	if info, ok := levelInfos[lvl]; ok {
+		return fmt.Errorf("level %d has been registered as %v", lvl, info.name)
+	}
+
+	levelNames[name] = lvl
+	levelInfos[lvl] = levelInfo{
+		name:  name,
+		color: c,
+	}
+
+	return nil
+}
+
+// StandardLevel gets the nearest standard level (trace, debug, info, warn,
+// error, fatal and panic) which is not higher than the given level. Custom
+// levels higher than error will be mapped to error, since fatal and panic
+// will exit or panic. This is used by slago logger implementations to map
+// levels into native levels.
+func StandardLevel(lvl Level) Level {
+	var result = TraceLevel
+	for _, l := range standardLevels {
+		if l <= lvl {
+			result = l
+		}
+	}
+
+	if result > ErrorLevel && result != lvl {
+		return ErrorLevel
+	}
+
+	return result
+}
+
+func (l Level) String() string {
+	levelLocker.RLock()
+	defer levelLocker.RUnlock()
+
+	if info, ok := levelInfos[l]; ok {
+		return info.name
+	}
+
+	return strconv.Itoa(int(l))
+}
+
+// ParseLevel converts a level string into slago level value. It will fall
+// back to trace level if the level string is invalid, use ParseLevelE if the
+// error is concerned.
+func ParseLevel(lvl string) Level {
+	level, err := ParseLevelE(lvl)
+	if err != nil {
+		return TraceLevel
+	}
+
+	return level
+}
+
+// ParseLevelE converts a level string into slago level value. The level string
+// can be a registered level name, an alias or the numeric value of a registered
+// level, and it is case insensitive. An error will be returned if the level string is invalid.
+func ParseLevelE(lvl string) (Level, error) {
+	name := strings.ToUpper(strings.TrimSpace(lvl))
+	if alias, ok := levelAliases[name]; ok {
+		name = alias
+	}
+
+	levelLocker.RLock()
+	defer levelLocker.RUnlock()
+
+	if level, ok := levelNames[name]; ok {
+		return level, nil
+	}
+
+	// only registered levels are accepted as number, so the numbers of old
+	// builtin levels (0 to 6) won't be parsed as other levels silently
+	if n, err := strconv.ParseInt(name, 10, 8); err == nil {
+		if _, ok := levelInfos[Level(n)]; ok {
+			return Level(n), nil
+		}
+		return TraceLevel, fmt.Errorf("unregistered level: %q", lvl)
+	}
+
+	return TraceLevel, fmt.Errorf("unknown level: %q", lvl)
+}
+
+// MarshalText marshals level into text, it implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText unmarshals text into level, it implements encoding.TextUnmarshaler.
+func (l *Level) UnmarshalText(text []byte) error {
+	if l == nil {
+		return errors.New("can't unmarshal level into a nil pointer")
+	}
+
+	level, err := ParseLevelE(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+
+	return nil
+}
+
+// MarshalJSON marshals level into json string, it implements json.Marshaler.
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.String())
+}
+
+// UnmarshalJSON unmarshals json string or number into level, it implements json.Unmarshaler.
+// The json null is a no-op as encoding/json does.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		// numeric level is also accepted in json
+		text = string(data)
+	}
+
+	return l.UnmarshalText([]byte(text))
+}
+
+// Set sets level from string, it implements flag.Value.
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// levelColor gets the color of given level.
+func levelColor(lvl Level) int {
+	levelLocker.RLock()
+	defer levelLocker.RUnlock()
+
+	info, ok := levelInfos[lvl]
+	if !ok {
+		return colorWhite
+	}
+
+	return info.color
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// levelHandler represents a http handler to get and change the levels of loggers.
+type levelHandler struct {
+	lc *LoggerContext
+}
+
+// levelRequest represents the request to change the level of logger, the
+// level will be reset if it's null.
+type levelRequest struct {
+	Name  string `json:"name"`
+	Level *Level `json:"level"`
+}
+
+// NewLevelHandler creates a new instance of http handler to manage levels of
+// loggers at runtime. GET lists all the loggers, or the logger specified by
+// query parameter `name` (404 if it's not created). PUT changes the level of
+// logger with json body like {"name":"github.com/acme/db","level":"DEBUG"},
+// and null level resets it.
+func NewLevelHandler() http.Handler {
+	return defaultContext.LevelHandler()
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		if len(name) == 0 {
+			h.writeJson(w, http.StatusOK, h.lc.Loggers())
+			return
+		}
+		// only the loggers which have been created can be queried
+		logger, ok := h.lc.existingLogger(name)
+		if !ok {
+			h.writeError(w, http.StatusNotFound, "logger not found")
+			return
+		}
+		h.writeJson(w, http.StatusOK, loggerLevel(logger))
+
+	case http.MethodPut:
+		var req levelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(req.Name) == 0 {
+			h.writeError(w, http.StatusBadRequest, "logger name is required")
+			return
+		}
+
+		logger := h.lc.Logger(req.Name).(*classicLogger)
+		if logger.levelFixed() {
+			h.writeError(w, http.StatusConflict, "level is fixed by environment variables or flags")
+			return
+		}
+		if req.Level == nil {
+			logger.ResetLevel()
+		} else {
+			logger.SetLevel(*req.Level)
+		}
+		h.writeJson(w, http.StatusOK, loggerLevel(logger))
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		h.writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *levelHandler) writeError(w http.ResponseWriter, code int, msg string) {
+	h.writeJson(w, code, map[string]string{"error": msg})
+}
+
+func (h *levelHandler) writeJson(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLevel(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "level test")
+}
+
+// register only once since all the specs are run in every test
+var auditLevelErr = RegisterLevel(35, "audit", "magenta")
+
+var _ = Describe("custom level", func() {
+	It("register", func() {
+		Expect(auditLevelErr).To(BeNil())
+		Expect(Level(35).String()).To(Equal("AUDIT"))
+		Expect(ParseLevel("audit")).To(Equal(Level(35)))
+		Expect(levelColor(35)).To(Equal(colorMagenta))
+	})
+	It("register duplicate", func() {
+		Expect(RegisterLevel(36, "AUDIT", "red")).NotTo(BeNil())
+		Expect(RegisterLevel(35, "AUDIT2", "red")).NotTo(BeNil())
+		Expect(RegisterLevel(37, "", "red")).NotTo(BeNil())
+		Expect(RegisterLevel(38, "UNKNOWN", "pink")).NotTo(BeNil())
+		Expect(RegisterLevel(OffLevel, "MAX", "red")).NotTo(BeNil())
+	})
+	It("unregistered level", func() {
+		Expect(Level(12).String()).To(Equal("12"))
+		_, err := ParseLevelE("12")
+		Expect(err).NotTo(BeNil())
+		Expect(ParseLevel("3")).To(Equal(TraceLevel))
+		Expect(ParseLevel("35")).To(Equal(Level(35)))
+		Expect(ParseLevel("unknown")).To(Equal(TraceLevel))
+	})
+	It("standard level", func() {
+		Expect(StandardLevel(NoticeLevel)).To(Equal(InfoLevel))
+		Expect(StandardLevel(35)).To(Equal(WarnLevel))
+		Expect(StandardLevel(55)).To(Equal(ErrorLevel))
+		Expect(StandardLevel(FatalLevel)).To(Equal(FatalLevel))
+	})
+	It("filter custom level", func() {
+		event := makeEvent([]byte(`{"level":"AUDIT","int":88}`))
+		Expect(NewLevelFilter(WarnLevel).Do(event)).To(Equal(false))
+		Expect(NewLevelFilter(ErrorLevel).Do(event)).To(Equal(true))
+		Expect(NewLevelFilter(OffLevel).Do(event)).To(Equal(true))
+		event = makeEvent([]byte(`{"level":"12","int":88}`))
+		Expect(NewLevelFilter(DebugLevel).Do(event)).To(Equal(false))
+		Expect(NewLevelFilter(InfoLevel).Do(event)).To(Equal(true))
+	})
+})
+
+var _ = Describe("parse level", func() {
+	It("parse with error", func() {
+		lvl, err := ParseLevelE("warning")
+		Expect(err).To(BeNil())
+		Expect(lvl).To(Equal(WarnLevel))
+		lvl, err = ParseLevelE(" Err ")
+		Expect(err).To(BeNil())
+		Expect(lvl).To(Equal(ErrorLevel))
+		lvl, err = ParseLevelE("25")
+		Expect(err).To(BeNil())
+		Expect(lvl).To(Equal(NoticeLevel))
+		_, err = ParseLevelE("unknown")
+		Expect(err).NotTo(BeNil())
+	})
+	It("text", func() {
+		var lvl Level
+		Expect(lvl.UnmarshalText([]byte("fatal"))).To(BeNil())
+		Expect(lvl).To(Equal(FatalLevel))
+		text, _ := lvl.MarshalText()
+		Expect(string(text)).To(Equal("FATAL"))
+		Expect(lvl.UnmarshalText([]byte("unknown"))).NotTo(BeNil())
+	})
+	It("json", func() {
+		var config struct {
+			Level  Level `json:"level"`
+			Number Level `json:"number"`
+		}
+		err := json.Unmarshal([]byte(`{"level":"info","number":30}`), &config)
+		Expect(err).To(BeNil())
+		Expect(config.Level).To(Equal(InfoLevel))
+		Expect(config.Number).To(Equal(WarnLevel))
+		data, _ := json.Marshal(config)
+		Expect(string(data)).To(Equal(`{"level":"INFO","number":"WARN"}`))
+		Expect(json.Unmarshal([]byte(`{"level":"bad"}`), &config)).NotTo(BeNil())
+	})
+	It("json null", func() {
+		var config struct {
+			Level Level `json:"level"`
+		}
+		config.Level = ErrorLevel
+		Expect(json.Unmarshal([]byte(`{"level":null}`), &config)).To(BeNil())
+		Expect(config.Level).To(Equal(ErrorLevel))
+	})
+	It("flag", func() {
+		var lvl = InfoLevel
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Var(&lvl, "level", "logging level")
+		Expect(fs.Parse([]string{"-level", "debug"})).To(BeNil())
+		Expect(lvl).To(Equal(DebugLevel))
+	})
+})
@@ -16,8 +16,9 @@ package slago
 
 // Lifecycle represents the lifecycle of component.
 type Lifecycle interface {
-	// Start the component.
-	Start()
+	// Start the component, an error will be returned if the component
+	// can't be started.
+	Start() error
 	// Stop the component.
 	Stop()
 }
@@ -0,0 +1,301 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LoggerContext represents an isolated logging context which owns its bound
+// slago logger, bridges, named logger tree, writers and levels.
+type LoggerContext struct {
+	locker  sync.RWMutex
+	loggers []SlaLogger
+	bridges []Bridge
+	binder  string
+	bound   SlaLogger
+	cache   map[string]*classicLogger
+
+	configLocker sync.Mutex
+	configured   map[string]bool
+	watcher      *configWatcher
+	overrides    *overrides
+	shutdown     int32
+}
+
+// LoggerLevel represents the level information of a named logger.
+type LoggerLevel struct {
+	Name           string `json:"name"`
+	Level          *Level `json:"level"`
+	EffectiveLevel Level  `json:"effective_level"`
+}
+
+// NewLoggerContext creates a new instance of LoggerContext.
+func NewLoggerContext() *LoggerContext {
+	return &LoggerContext{
+		loggers: make([]SlaLogger, 0),
+		bridges: make([]Bridge, 0),
+	}
+}
+
+// Bind binds an implementation of slago logger as output logger. An error will
+// be returned if the binder conflicts with installed bridges, or the binder has
+// been bound to another context.
+func (lc *LoggerContext) Bind(logger SlaLogger) error {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	lc.loggers = append(lc.loggers, logger)
+	if err := lc.checkBinders(); err != nil {
+		lc.loggers = lc.loggers[:len(lc.loggers)-1]
+		return err
+	}
+	if err := claimBinder(lc, logger); err != nil {
+		lc.loggers = lc.loggers[:len(lc.loggers)-1]
+		return err
+	}
+
+	return nil
+}
+
+// Install installs a logging framework bridge into this context. An error will
+// be returned if the bridge conflicts with any bound binder.
+func (lc *LoggerContext) Install(bridge Bridge) error {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	lc.bridges = append(lc.bridges, bridge)
+	if err := lc.checkBinders(); err != nil {
+		lc.bridges = lc.bridges[:len(lc.bridges)-1]
+		return err
+	}
+
+	return nil
+}
+
+// Logger gets a slago logger with the given name in this context. The root
+// logger will be returned if no name is given.
+func (lc *LoggerContext) Logger(name ...string) SlaLogger {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	lc.init()
+
+	var realName string
+	if len(name) > 0 {
+		realName = name[0]
+	}
+
+	return lc.findLogger(realName)
+}
+
+// SetAdditive sets additivity of the logger with given name.
+func (lc *LoggerContext) SetAdditive(name string, additive bool) {
+	lc.Logger(name).(*classicLogger).SetAdditive(additive)
+}
+
+// Loggers gets level information of all the named loggers sorted by name.
+func (lc *LoggerContext) Loggers() []LoggerLevel {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	lc.init()
+
+	names := make([]string, 0, len(lc.cache))
+	for name := range lc.cache {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	levels := make([]LoggerLevel, 0, len(names))
+	for _, name := range names {
+		levels = append(levels, loggerLevel(lc.cache[name]))
+	}
+
+	return levels
+}
+
+// EffectiveLevel gets the level of the logger with given name.
+func (lc *LoggerContext) EffectiveLevel(name string) Level {
+	return lc.Logger(name).(*classicLogger).EffectiveLevel()
+}
+
+// ResetLevel resets the level of the logger with given name.
+func (lc *LoggerContext) ResetLevel(name string) {
+	lc.Logger(name).(*classicLogger).ResetLevel()
+}
+
+// LevelHandler creates a http handler to manage levels of loggers in this context.
+func (lc *LoggerContext) LevelHandler() http.Handler {
+	return &levelHandler{
+		lc: lc,
+	}
+}
+
+// Reset stops and removes all the writers, and removes bound slago logger,
+// bridges and named loggers, then this context can be configured again.
+func (lc *LoggerContext) Reset() {
+	lc.setWatcher(nil)
+
+	lc.configLocker.Lock()
+	lc.configured = nil
+	lc.configLocker.Unlock()
+
+	lc.locker.Lock()
+	cache := lc.cache
+	lc.loggers = make([]SlaLogger, 0)
+	lc.bridges = make([]Bridge, 0)
+	lc.binder = ""
+	lc.bound = nil
+	lc.cache = nil
+	lc.overrides = nil
+	atomic.StoreInt32(&lc.shutdown, 0)
+	lc.locker.Unlock()
+	deactivateContext(lc)
+	releaseBinders(lc)
+
+	// writers are reset without lock, since the events being written
+	// will lookup loggers in this context
+	for _, logger := range cache {
+		logger.ResetWriter()
+		// this will remove the dispatch writer of this context
+		logger.root.ResetWriter()
+	}
+}
+
+func (lc *LoggerContext) init() {
+	if lc.cache != nil {
+		return
+	}
+
+	lc.bound = lc.selectBinder()
+	if _, ok := lc.bound.(OutputLogger); !ok {
+		// the bound logger dispatches all the events to the writers of
+		// named loggers with the logger name in events
+		lc.bound.AddWriter(newDispatchWriter(lc, nil))
+	}
+	lc.cache = make(map[string]*classicLogger)
+	lc.cache[RootLoggerName] = lc.newLogger(RootLoggerName, nil)
+	activateContext(lc)
+
+	// the settings from environment variables and flags override the others
+	root, writer := lc.applyOverrideLevels()
+	if writer != nil {
+		root.fixWriters(writer)
+	}
+}
+
+func (lc *LoggerContext) findLogger(name string) *classicLogger {
+	rootLogger := lc.cache[RootLoggerName]
+	if len(name) == 0 {
+		return rootLogger
+	}
+
+	child, ok := lc.cache[name]
+	if ok {
+		return child
+	}
+
+	var i = 0
+	var logger = rootLogger
+	var childName string
+	for {
+		index := indexOfSlash(name, i)
+		if index == -1 {
+			childName = name
+		} else {
+			childName = name[:index]
+		}
+		i = index + 1
+		child, ok = lc.cache[childName]
+		if !ok {
+			child = lc.newLogger(childName, logger)
+			lc.cache[childName] = child
+		}
+		logger = child
+
+		if index == -1 {
+			return child
+		}
+	}
+}
+
+// newLogger creates a named logger with the bound logger. If the bound logger
+// implements OutputLogger, the events will be written into the writers of
+// the named logger directly without looking up by name.
+func (lc *LoggerContext) newLogger(name string, parent SlaLogger) *classicLogger {
+	logger := newClassicLogger(name, lc.bound, parent).(*classicLogger)
+	if ol, ok := lc.bound.(OutputLogger); ok {
+		logger.root = ol.Output(newDispatchWriter(lc, logger))
+	}
+
+	return logger
+}
+
+// existingLogger gets the logger with given name without creating it.
+func (lc *LoggerContext) existingLogger(name string) (*classicLogger, bool) {
+	lc.locker.Lock()
+	defer lc.locker.Unlock()
+
+	lc.init()
+
+	if len(name) == 0 {
+		name = RootLoggerName
+	}
+	logger, ok := lc.cache[name]
+	return logger, ok
+}
+
+// lookupLogger finds the nearest named logger for the given name, nil will
+// be returned if this context has been reset.
+func (lc *LoggerContext) lookupLogger(name string) *classicLogger {
+	lc.locker.RLock()
+	defer lc.locker.RUnlock()
+
+	if lc.cache == nil {
+		return nil
+	}
+
+	for len(name) > 0 {
+		if logger, ok := lc.cache[name]; ok {
+			return logger
+		}
+
+		index := strings.LastIndex(name, slash)
+		if index == -1 {
+			break
+		}
+		name = name[:index]
+	}
+
+	return lc.cache[RootLoggerName]
+}
+
+func loggerLevel(cl *classicLogger) LoggerLevel {
+	var level *Level
+	if lvl, ok := cl.levelSet(); ok {
+		level = &lvl
+	}
+
+	return LoggerLevel{
+		Name:           cl.name,
+		Level:          level,
+		EffectiveLevel: cl.EffectiveLevel(),
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestLoggerContext(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "logger context test")
+}
+
+var _ = Describe("logger context", func() {
+	It("isolated", func() {
+		lc1 := NewLoggerContext()
+		lc2 := NewLoggerContext()
+		lc1.Bind(NewNativeLogger())
+		lc2.Bind(NewNativeLogger())
+
+		lc1.Logger("acme").SetLevel(ErrorLevel)
+		Expect(lc1.EffectiveLevel("acme/db")).To(Equal(ErrorLevel))
+		Expect(lc2.EffectiveLevel("acme/db")).To(Equal(TraceLevel))
+		Expect(lc1.Loggers()).To(HaveLen(3))
+		Expect(lc2.Loggers()).To(HaveLen(3))
+	})
+	It("binder shared by contexts", func() {
+		lc1 := NewLoggerContext()
+		lc2 := NewLoggerContext()
+		binder := NewNativeLogger()
+		Expect(lc1.Bind(binder)).To(BeNil())
+		Expect(lc2.Bind(binder)).NotTo(BeNil())
+		Expect(lc2.Binders()).To(BeEmpty())
+
+		lc1.Reset()
+		Expect(lc2.Bind(binder)).To(BeNil())
+		lc2.Reset()
+	})
+	It("dispatch and reset", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		buf := &bytes.Buffer{}
+		lc.Logger("acme").AddWriter(&bufferWriter{buf})
+
+		dispatcher := newDispatchWriter(lc, nil)
+		_, _ = dispatcher.Write([]byte(`{"logger_name":"acme/db"}`))
+		_, _ = dispatcher.Write([]byte(`{"logger_name":"other"}`))
+		Expect(buf.String()).To(Equal(`{"logger_name":"acme/db"}`))
+
+		lc.Reset()
+		_, _ = dispatcher.Write([]byte(`{"logger_name":"acme/db"}`))
+		Expect(buf.String()).To(Equal(`{"logger_name":"acme/db"}`))
+		Expect(lc.Loggers()).To(HaveLen(1))
+	})
+	It("route by logger", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		rootBuf, dbBuf := &bytes.Buffer{}, &bytes.Buffer{}
+		lc.Logger().AddWriter(&bufferWriter{rootBuf})
+		lc.Logger("acme/db").AddWriter(&bufferWriter{dbBuf})
+		lc.SetAdditive("acme/db", false)
+
+		lc.Logger("acme/db/pool").Info().Msg("db")
+		lc.Logger().Info().Msg("root")
+		Expect(dbBuf.String()).To(ContainSubstring(`"logger_name":"acme/db/pool"`))
+		Expect(rootBuf.String()).NotTo(ContainSubstring(`"db"`))
+		Expect(rootBuf.String()).To(ContainSubstring(`"message":"root"`))
+		Expect(rootBuf.String()).NotTo(ContainSubstring(LoggerFieldKey))
+		lc.Reset()
+	})
+	It("select binder", func() {
+		lc := NewLoggerContext()
+		zap := &namedLogger{NewNativeLogger(), "go.uber.org/zap"}
+		zero := &namedLogger{NewNativeLogger(), "github.com/rs/zerolog"}
+		Expect(lc.Bind(zap)).To(BeNil())
+		Expect(lc.Bind(zero)).To(BeNil())
+		Expect(lc.Binders()).To(Equal([]string{"go.uber.org/zap", "github.com/rs/zerolog"}))
+
+		Expect(lc.UseBinder("zerolog")).To(BeNil())
+		Expect(lc.Logger().(*classicLogger).root).To(BeIdenticalTo(zero))
+		lc.Reset()
+	})
+	It("binder conflicts with bridge", func() {
+		lc := NewLoggerContext()
+		zap := &namedLogger{NewNativeLogger(), "go.uber.org/zap"}
+		zero := &namedLogger{NewNativeLogger(), "github.com/rs/zerolog"}
+		Expect(lc.Install(&namedBridge{"go.uber.org/zap"})).To(BeNil())
+		Expect(lc.Bind(zap)).NotTo(BeNil())
+		Expect(lc.Bind(zero)).To(BeNil())
+		Expect(lc.Bind(zap)).NotTo(BeNil())
+		Expect(lc.Binders()).To(Equal([]string{"github.com/rs/zerolog"}))
+		Expect(lc.Install(&namedBridge{"github.com/rs/zerolog"})).NotTo(BeNil())
+		Expect(lc.Logger().(*classicLogger).root).To(BeIdenticalTo(zero))
+		lc.Reset()
+
+		Expect(lc.Bind(zero)).To(BeNil())
+		Expect(lc.Bind(zap)).To(BeNil())
+		Expect(lc.Install(&namedBridge{"go.uber.org/zap"})).NotTo(BeNil())
+		lc.Reset()
+	})
+})
+
+type namedLogger struct {
+	SlaLogger
+	name string
+}
+
+func (l *namedLogger) Name() string {
+	return l.name
+}
+
+type namedBridge struct {
+	name string
+}
+
+func (b *namedBridge) Name() string {
+	return b.name
+}
+
+func (b *namedBridge) ParseLevel(lvl string) Level {
+	return ParseLevel(lvl)
+}
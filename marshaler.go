@@ -0,0 +1,87 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"time"
+)
+
+// ObjectEncoder is used to add fields into a nested object. It's the same as
+// Record, but Msg and Msgf take no effect on it.
+type ObjectEncoder = Record
+
+// ObjectMarshaler allows user-defined types to encode themselves as a nested object.
+type ObjectMarshaler interface {
+	// MarshalSlagoObject adds fields of current object into encoder.
+	MarshalSlagoObject(enc ObjectEncoder) error
+}
+
+// ObjectMarshalerFunc is a type adapter that turns a function into an ObjectMarshaler.
+type ObjectMarshalerFunc func(enc ObjectEncoder) error
+
+// MarshalSlagoObject calls the underlying function.
+func (f ObjectMarshalerFunc) MarshalSlagoObject(enc ObjectEncoder) error {
+	return f(enc)
+}
+
+// ArrayMarshaler allows user-defined types to encode themselves as an array.
+type ArrayMarshaler interface {
+	// MarshalSlagoArray appends elements of current array into encoder.
+	MarshalSlagoArray(enc ArrayEncoder) error
+}
+
+// ArrayMarshalerFunc is a type adapter that turns a function into an ArrayMarshaler.
+type ArrayMarshalerFunc func(enc ArrayEncoder) error
+
+// MarshalSlagoArray calls the underlying function.
+func (f ArrayMarshalerFunc) MarshalSlagoArray(enc ArrayEncoder) error {
+	return f(enc)
+}
+
+// ArrayEncoder represents an encoder to append elements into an array.
+type ArrayEncoder interface {
+	// AppendStr appends string value to this array.
+	AppendStr(val string)
+
+	// AppendBool appends bool value to this array.
+	AppendBool(val bool)
+
+	// AppendInt appends int value to this array.
+	AppendInt(val int)
+
+	// AppendInt64 appends int64 value to this array.
+	AppendInt64(val int64)
+
+	// AppendUint appends uint value to this array.
+	AppendUint(val uint)
+
+	// AppendUint64 appends uint64 value to this array.
+	AppendUint64(val uint64)
+
+	// AppendFloat64 appends float64 value to this array.
+	AppendFloat64(val float64)
+
+	// AppendTime appends time value to this array.
+	AppendTime(val time.Time)
+
+	// AppendDur appends duration value to this array.
+	AppendDur(val time.Duration)
+
+	// AppendObject appends a nested object to this array.
+	AppendObject(obj ObjectMarshaler)
+
+	// AppendInterface appends interface value to this array.
+	AppendInterface(val interface{})
+}
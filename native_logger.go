@@ -0,0 +1,134 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// NativeLoggerName is the name of native slago logger.
+const NativeLoggerName = "github.com/coolerfall/slago/binder/slanative"
+
+// nativeLogger is an implementation of SlaLogger without any third-party logging
+// framework, the records are encoded into json and written into writers directly.
+type nativeLogger struct {
+	level       *int32
+	fields      []byte
+	multiWriter *MultiWriter
+}
+
+// NewNativeLogger creates a new instance of native slago logger. This logger
+// will be used if no other slago logger is bound.
+func NewNativeLogger() SlaLogger {
+	level := int32(TraceLevel)
+	return &nativeLogger{
+		level:       &level,
+		multiWriter: NewMultiWriter(),
+	}
+}
+
+func (l *nativeLogger) Name() string {
+	return NativeLoggerName
+}
+
+func (l *nativeLogger) AddWriter(w ...Writer) {
+	l.multiWriter.AddWriter(w...)
+}
+
+func (l *nativeLogger) ResetWriter() {
+	l.multiWriter.Reset()
+}
+
+func (l *nativeLogger) SetLevel(lvl Level) {
+	atomic.StoreInt32(l.level, int32(lvl))
+}
+
+func (l *nativeLogger) With() *FieldContext {
+	r := newNestedNativeRecord()
+	r.buf = append(r.buf, l.fields...)
+	return NewFieldContext(r, func() SlaLogger {
+		return &nativeLogger{
+			level:       l.level,
+			fields:      append([]byte(nil), r.buf...),
+			multiWriter: l.multiWriter,
+		}
+	})
+}
+
+func (l *nativeLogger) Output(w Writer) SlaLogger {
+	multiWriter := NewMultiWriter()
+	multiWriter.AddWriter(w)
+	return &nativeLogger{
+		level:       l.level,
+		fields:      l.fields,
+		multiWriter: multiWriter,
+	}
+}
+
+func (l *nativeLogger) Ctx(ctx context.Context) SlaLogger {
+	return l.With().Ctx(ctx).Logger()
+}
+
+func (l *nativeLogger) Trace() Record {
+	return l.Level(TraceLevel)
+}
+
+func (l *nativeLogger) Debug() Record {
+	return l.Level(DebugLevel)
+}
+
+func (l *nativeLogger) Info() Record {
+	return l.Level(InfoLevel)
+}
+
+func (l *nativeLogger) Notice() Record {
+	return l.Level(NoticeLevel)
+}
+
+func (l *nativeLogger) Warn() Record {
+	return l.Level(WarnLevel)
+}
+
+func (l *nativeLogger) Error() Record {
+	return l.Level(ErrorLevel)
+}
+
+func (l *nativeLogger) Fatal() Record {
+	return l.Level(FatalLevel)
+}
+
+func (l *nativeLogger) Panic() Record {
+	return l.Level(PanicLevel)
+}
+
+// Level creates a record with the given level, the name of custom level
+// will be written as it is.
+func (l *nativeLogger) Level(lvl Level) Record {
+	min := Level(atomic.LoadInt32(l.level))
+	if lvl < min || min >= OffLevel {
+		return newNoopRecord()
+	}
+
+	return newNativeRecord(l, lvl)
+}
+
+func (l *nativeLogger) WriteRaw(p []byte) {
+	_, err := l.multiWriter.Write(p)
+	if err != nil {
+		// report to status manager instead of logging into the failed writer
+		defaultStatusManager.Error("native logger", "write raw error: %v", err)
+	}
+}
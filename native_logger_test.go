@@ -0,0 +1,109 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestNativeLogger(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "native logger test")
+}
+
+var _ = Describe("native logger", func() {
+	var buf *bytes.Buffer
+	var lc *LoggerContext
+	decode := func() map[string]interface{} {
+		m := make(map[string]interface{})
+		Expect(json.Unmarshal(buf.Bytes(), &m)).To(BeNil())
+		buf.Reset()
+		return m
+	}
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lc = NewLoggerContext()
+		lc.Logger().AddWriter(&bufferWriter{buf})
+	})
+	AfterEach(func() {
+		lc.Reset()
+	})
+
+	It("default binder", func() {
+		Expect(lc.Logger().(*classicLogger).root.Name()).To(Equal(NativeLoggerName))
+	})
+	It("encode fields", func() {
+		lc.Logger("acme").Notice().Str("str", "a\"b\n\x01").Ints("ints", []int{1, 2}).
+			Bool("bool", true).Float64("nan", math.NaN()).Dur("dur", 1500*time.Microsecond).
+			IPAddr("ip", net.IPv4(127, 0, 0, 1)).Hex("hex", []byte{0xab}).
+			Interface("iface", map[string]int{"a": 1}).Err(errors.New("failed")).
+			Timestamp(time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)).Msgf("hello %s", "slago")
+
+		m := decode()
+		Expect(m[LevelFieldKey]).To(Equal("NOTICE"))
+		Expect(m[LoggerFieldKey]).To(Equal("acme"))
+		Expect(m[MessageFieldKey]).To(Equal("hello slago"))
+		Expect(m[TimestampFieldKey]).To(Equal("2021-01-02T03:04:05Z"))
+		Expect(m["str"]).To(Equal("a\"b\n\x01"))
+		Expect(m["ints"]).To(Equal([]interface{}{1.0, 2.0}))
+		Expect(m["bool"]).To(Equal(true))
+		Expect(m["nan"]).To(Equal("NaN"))
+		Expect(m["dur"]).To(Equal(1.5))
+		Expect(m["ip"]).To(Equal("127.0.0.1"))
+		Expect(m["hex"]).To(Equal("ab"))
+		Expect(m["iface"]).To(Equal(map[string]interface{}{"a": 1.0}))
+		Expect(m[ErrorFieldKey]).To(HaveKeyWithValue("message", "failed"))
+	})
+	It("nested fields", func() {
+		lc.Logger().Info().Dict("dict", func(r Record) {
+			r.Str("k", "v").Dict("empty", func(r Record) {})
+		}).Array("arr", ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+			enc.AppendStr("a")
+			enc.AppendObject(ObjectMarshalerFunc(func(enc ObjectEncoder) error {
+				enc.Int("n", 1)
+				return nil
+			}))
+			return nil
+		})).Msg()
+
+		m := decode()
+		Expect(m["dict"]).To(Equal(map[string]interface{}{
+			"k": "v", "empty": map[string]interface{}{},
+		}))
+		Expect(m["arr"]).To(Equal([]interface{}{"a", map[string]interface{}{"n": 1.0}}))
+	})
+	It("with fields and level", func() {
+		logger := lc.Logger().With().Str("app", "slago").Logger()
+		logger.Info().Msg("with")
+		Expect(decode()).To(HaveKeyWithValue("app", "slago"))
+
+		logger.SetLevel(WarnLevel)
+		logger.Info().Msg("ignored")
+		Expect(buf.Len()).To(Equal(0))
+		logger.SetLevel(OffLevel)
+		logger.Error().Msg("ignored")
+		Expect(buf.Len()).To(Equal(0))
+	})
+})
@@ -0,0 +1,650 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	hexDigits = "0123456789abcdef"
+	// the buffer larger than this will not be put back to pool
+	maxPooledBufferSize = 1 << 16
+)
+
+var nativeRecordPool = &sync.Pool{
+	New: func() interface{} {
+		return &nativeRecord{
+			buf: make([]byte, 0, 512),
+		}
+	},
+}
+
+// nativeRecord is an implementation of Record which encodes fields into json
+// directly. The record without logger is used to collect fields of nested
+// object, each field in buffer starts with a comma.
+type nativeRecord struct {
+	logger *nativeLogger
+	level  Level
+	time   time.Time
+	buf    []byte
+}
+
+func newNativeRecord(logger *nativeLogger, lvl Level) *nativeRecord {
+	r := nativeRecordPool.Get().(*nativeRecord)
+	r.logger = logger
+	r.level = lvl
+	r.time = time.Time{}
+	r.buf = append(r.buf[:0], '{')
+	r.buf = appendJsonString(append(r.buf, `"`+LevelFieldKey+`":`...), lvl.String())
+	r.buf = append(r.buf, logger.fields...)
+
+	return r
+}
+
+// newNestedNativeRecord creates a record without logger to collect fields.
+func newNestedNativeRecord() *nativeRecord {
+	return &nativeRecord{
+		buf: make([]byte, 0, 64),
+	}
+}
+
+// putNativeRecord puts the record back to pool.
+func putNativeRecord(r *nativeRecord) {
+	if cap(r.buf) > maxPooledBufferSize {
+		return
+	}
+	r.logger = nil
+	nativeRecordPool.Put(r)
+}
+
+func (r *nativeRecord) Str(key, val string) Record {
+	r.buf = appendJsonString(r.appendKey(key), val)
+	return r
+}
+
+func (r *nativeRecord) Strs(key string, val []string) Record {
+	r.buf = r.appendKey(key)
+	r.buf = append(r.buf, '[')
+	for i, v := range val {
+		if i > 0 {
+			r.buf = append(r.buf, ',')
+		}
+		r.buf = appendJsonString(r.buf, v)
+	}
+	r.buf = append(r.buf, ']')
+	return r
+}
+
+func (r *nativeRecord) Bytes(key string, val []byte) Record {
+	r.buf = appendJsonString(r.appendKey(key), string(val))
+	return r
+}
+
+func (r *nativeRecord) Hex(key string, val []byte) Record {
+	r.buf = r.appendKey(key)
+	r.buf = append(r.buf, '"')
+	r.buf = append(r.buf, hex.EncodeToString(val)...)
+	r.buf = append(r.buf, '"')
+	return r
+}
+
+func (r *nativeRecord) Err(err error) Record {
+	if err == nil {
+		return r
+	}
+
+	r.Object(ErrorFieldKey, ErrorObject(err))
+	return AppendErrorStack(r, err)
+}
+
+func (r *nativeRecord) Stack() Record {
+	return r.Strs(StackFieldKey, Stack(0))
+}
+
+func (r *nativeRecord) Errs(key string, errs []error) Record {
+	r.buf = r.appendKey(key)
+	r.buf = append(r.buf, '[')
+	for i, err := range errs {
+		if i > 0 {
+			r.buf = append(r.buf, ',')
+		}
+		if err == nil {
+			r.buf = append(r.buf, "null"...)
+		} else {
+			r.buf = appendJsonString(r.buf, err.Error())
+		}
+	}
+	r.buf = append(r.buf, ']')
+	return r
+}
+
+func (r *nativeRecord) Bool(key string, val bool) Record {
+	r.buf = strconv.AppendBool(r.appendKey(key), val)
+	return r
+}
+
+func (r *nativeRecord) Bools(key string, val []bool) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendBool(r.buf, val[i])
+	})
+}
+
+func (r *nativeRecord) Int(key string, val int) Record {
+	return r.Int64(key, int64(val))
+}
+
+func (r *nativeRecord) Ints(key string, val []int) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendInt(r.buf, int64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Int8(key string, val int8) Record {
+	return r.Int64(key, int64(val))
+}
+
+func (r *nativeRecord) Ints8(key string, val []int8) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendInt(r.buf, int64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Int16(key string, val int16) Record {
+	return r.Int64(key, int64(val))
+}
+
+func (r *nativeRecord) Ints16(key string, val []int16) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendInt(r.buf, int64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Int32(key string, val int32) Record {
+	return r.Int64(key, int64(val))
+}
+
+func (r *nativeRecord) Ints32(key string, val []int32) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendInt(r.buf, int64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Int64(key string, val int64) Record {
+	r.buf = strconv.AppendInt(r.appendKey(key), val, 10)
+	return r
+}
+
+func (r *nativeRecord) Ints64(key string, val []int64) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendInt(r.buf, val[i], 10)
+	})
+}
+
+func (r *nativeRecord) Uint(key string, val uint) Record {
+	return r.Uint64(key, uint64(val))
+}
+
+func (r *nativeRecord) Uints(key string, val []uint) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendUint(r.buf, uint64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Uint8(key string, val uint8) Record {
+	return r.Uint64(key, uint64(val))
+}
+
+func (r *nativeRecord) Uints8(key string, val []uint8) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendUint(r.buf, uint64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Uint16(key string, val uint16) Record {
+	return r.Uint64(key, uint64(val))
+}
+
+func (r *nativeRecord) Uints16(key string, val []uint16) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendUint(r.buf, uint64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Uint32(key string, val uint32) Record {
+	return r.Uint64(key, uint64(val))
+}
+
+func (r *nativeRecord) Uints32(key string, val []uint32) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendUint(r.buf, uint64(val[i]), 10)
+	})
+}
+
+func (r *nativeRecord) Uint64(key string, val uint64) Record {
+	r.buf = strconv.AppendUint(r.appendKey(key), val, 10)
+	return r
+}
+
+func (r *nativeRecord) Uints64(key string, val []uint64) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = strconv.AppendUint(r.buf, val[i], 10)
+	})
+}
+
+func (r *nativeRecord) Float32(key string, val float32) Record {
+	r.buf = appendFloat(r.appendKey(key), float64(val), 32)
+	return r
+}
+
+func (r *nativeRecord) Floats32(key string, val []float32) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = appendFloat(r.buf, float64(val[i]), 32)
+	})
+}
+
+func (r *nativeRecord) Float64(key string, val float64) Record {
+	r.buf = appendFloat(r.appendKey(key), val, 64)
+	return r
+}
+
+func (r *nativeRecord) Floats64(key string, val []float64) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = appendFloat(r.buf, val[i], 64)
+	})
+}
+
+func (r *nativeRecord) Time(key string, val time.Time) Record {
+	r.buf = appendTime(r.appendKey(key), val)
+	return r
+}
+
+func (r *nativeRecord) Times(key string, val []time.Time) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = appendTime(r.buf, val[i])
+	})
+}
+
+// Dur adds duration in milliseconds to this record.
+func (r *nativeRecord) Dur(key string, val time.Duration) Record {
+	r.buf = appendDur(r.appendKey(key), val)
+	return r
+}
+
+func (r *nativeRecord) Durs(key string, val []time.Duration) Record {
+	return r.appendArray(key, len(val), func(i int) {
+		r.buf = appendDur(r.buf, val[i])
+	})
+}
+
+func (r *nativeRecord) RawJSON(key string, val []byte) Record {
+	r.buf = append(r.appendKey(key), val...)
+	return r
+}
+
+func (r *nativeRecord) Stringer(key string, val fmt.Stringer) Record {
+	if val == nil {
+		return r.Interface(key, nil)
+	}
+
+	return r.Str(key, val.String())
+}
+
+func (r *nativeRecord) IPAddr(key string, ip net.IP) Record {
+	return r.Str(key, ip.String())
+}
+
+func (r *nativeRecord) IPPrefix(key string, pfx net.IPNet) Record {
+	return r.Str(key, pfx.String())
+}
+
+func (r *nativeRecord) MACAddr(key string, ha net.HardwareAddr) Record {
+	return r.Str(key, ha.String())
+}
+
+func (r *nativeRecord) URL(key string, val *url.URL) Record {
+	if val == nil {
+		return r.Interface(key, nil)
+	}
+
+	return r.Str(key, val.String())
+}
+
+func (r *nativeRecord) Dict(key string, f func(r Record)) Record {
+	d := newNestedNativeRecord()
+	f(d)
+	r.buf = d.appendObject(r.appendKey(key))
+	return r
+}
+
+func (r *nativeRecord) Object(key string, obj ObjectMarshaler) Record {
+	r.buf = appendObject(r.appendKey(key), obj)
+	return r
+}
+
+func (r *nativeRecord) Array(key string, arr ArrayMarshaler) Record {
+	enc := &nativeArrayEncoder{buf: append(r.appendKey(key), '[')}
+	_ = arr.MarshalSlagoArray(enc)
+	r.buf = append(enc.buf, ']')
+	return r
+}
+
+func (r *nativeRecord) Interface(key string, val interface{}) Record {
+	switch v := val.(type) {
+	case ObjectMarshaler:
+		return r.Object(key, v)
+	case ArrayMarshaler:
+		return r.Array(key, v)
+	}
+
+	r.buf = appendInterface(r.appendKey(key), val)
+	return r
+}
+
+func (r *nativeRecord) Any(key string, val interface{}) Record {
+	return AppendAny(r, key, val)
+}
+
+func (r *nativeRecord) Fields(fields map[string]interface{}) Record {
+	return AppendFields(r, fields)
+}
+
+func (r *nativeRecord) Marker(names ...string) Record {
+	if len(names) == 0 {
+		return r
+	}
+
+	return r.Strs(MarkerFieldKey, names)
+}
+
+func (r *nativeRecord) Ctx(ctx context.Context) Record {
+	return ExtractContext(ctx, r)
+}
+
+func (r *nativeRecord) Func(key string, f func() interface{}) Record {
+	if r.Enabled() {
+		r.Any(key, f())
+	}
+	return r
+}
+
+func (r *nativeRecord) Lazy(f func(r Record)) Record {
+	if r.Enabled() {
+		f(r)
+	}
+	return r
+}
+
+// Enabled always returns true, since the disabled record is noop record.
+func (r *nativeRecord) Enabled() bool {
+	return true
+}
+
+func (r *nativeRecord) Timestamp(t time.Time) Record {
+	r.time = t
+	return r
+}
+
+func (r *nativeRecord) Msg(originMsg ...string) {
+	// nested record has no logger, just ignore it
+	if r.logger == nil {
+		return
+	}
+
+	AppendCaller(r)
+
+	var msg string
+	if len(originMsg) != 0 {
+		msg = originMsg[0]
+	}
+	r.write(msg)
+}
+
+func (r *nativeRecord) Msgf(format string, v ...interface{}) {
+	if r.logger == nil {
+		return
+	}
+
+	AppendCaller(r)
+
+	r.write(fmt.Sprintf(format, v...))
+}
+
+func (r *nativeRecord) Msgt(template string, args ...interface{}) {
+	r.Msg(AppendTemplate(r, template, args...))
+}
+
+// write writes the record into writers, the process will be terminated after
+// fatal or panic record is written, and slago will be shut down before that.
+func (r *nativeRecord) write(msg string) {
+	t := r.time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	r.Time(TimestampFieldKey, t)
+	r.Str(MessageFieldKey, msg)
+	r.buf = append(r.buf, '}', '\n')
+
+	level := StandardLevel(r.level)
+	if _, err := r.logger.multiWriter.Write(r.buf); err != nil {
+		defaultStatusManager.Error("native logger", "write error: %v", err)
+	}
+	putNativeRecord(r)
+
+	switch level {
+	case FatalLevel:
+		ShutdownBeforeExit()
+		os.Exit(1)
+	case PanicLevel:
+		ShutdownBeforeExit()
+		panic(msg)
+	}
+}
+
+// appendKey appends a comma and the key of field into buffer.
+func (r *nativeRecord) appendKey(key string) []byte {
+	r.buf = append(r.buf, ',')
+	r.buf = appendJsonString(r.buf, key)
+	return append(r.buf, ':')
+}
+
+// appendArray appends an array with the given length, each element is appended by f.
+func (r *nativeRecord) appendArray(key string, n int, f func(i int)) Record {
+	r.buf = append(r.appendKey(key), '[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			r.buf = append(r.buf, ',')
+		}
+		f(i)
+	}
+	r.buf = append(r.buf, ']')
+	return r
+}
+
+// appendObject appends the collected fields of nested record as an object.
+func (r *nativeRecord) appendObject(dst []byte) []byte {
+	dst = append(dst, '{')
+	if len(r.buf) != 0 {
+		// remove the leading comma
+		dst = append(dst, r.buf[1:]...)
+	}
+	return append(dst, '}')
+}
+
+// nativeArrayEncoder is an implementation of ArrayEncoder for native record.
+type nativeArrayEncoder struct {
+	buf []byte
+}
+
+// appendElement appends a comma into buffer if it's not the first element.
+func (e *nativeArrayEncoder) appendElement() {
+	if e.buf[len(e.buf)-1] != '[' {
+		e.buf = append(e.buf, ',')
+	}
+}
+
+func (e *nativeArrayEncoder) AppendStr(val string) {
+	e.appendElement()
+	e.buf = appendJsonString(e.buf, val)
+}
+
+func (e *nativeArrayEncoder) AppendBool(val bool) {
+	e.appendElement()
+	e.buf = strconv.AppendBool(e.buf, val)
+}
+
+func (e *nativeArrayEncoder) AppendInt(val int) {
+	e.AppendInt64(int64(val))
+}
+
+func (e *nativeArrayEncoder) AppendInt64(val int64) {
+	e.appendElement()
+	e.buf = strconv.AppendInt(e.buf, val, 10)
+}
+
+func (e *nativeArrayEncoder) AppendUint(val uint) {
+	e.AppendUint64(uint64(val))
+}
+
+func (e *nativeArrayEncoder) AppendUint64(val uint64) {
+	e.appendElement()
+	e.buf = strconv.AppendUint(e.buf, val, 10)
+}
+
+func (e *nativeArrayEncoder) AppendFloat64(val float64) {
+	e.appendElement()
+	e.buf = appendFloat(e.buf, val, 64)
+}
+
+func (e *nativeArrayEncoder) AppendTime(val time.Time) {
+	e.appendElement()
+	e.buf = appendTime(e.buf, val)
+}
+
+func (e *nativeArrayEncoder) AppendDur(val time.Duration) {
+	e.appendElement()
+	e.buf = appendDur(e.buf, val)
+}
+
+func (e *nativeArrayEncoder) AppendObject(obj ObjectMarshaler) {
+	e.appendElement()
+	e.buf = appendObject(e.buf, obj)
+}
+
+func (e *nativeArrayEncoder) AppendInterface(val interface{}) {
+	e.appendElement()
+	e.buf = appendInterface(e.buf, val)
+}
+
+// appendObject appends the object encoded by ObjectMarshaler into buffer.
+func appendObject(dst []byte, obj ObjectMarshaler) []byte {
+	r := newNestedNativeRecord()
+	_ = obj.MarshalSlagoObject(r)
+	return r.appendObject(dst)
+}
+
+// appendInterface appends the value marshaled by encoding/json into buffer.
+func appendInterface(dst []byte, val interface{}) []byte {
+	p, err := json.Marshal(val)
+	if err != nil {
+		return appendJsonString(dst, fmt.Sprintf("marshaling error: %v", err))
+	}
+
+	return append(dst, p...)
+}
+
+// appendFloat appends float into buffer, NaN and infinity are quoted since
+// they are not supported in json.
+func appendFloat(dst []byte, val float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(val):
+		return append(dst, `"NaN"`...)
+	case math.IsInf(val, 1):
+		return append(dst, `"+Inf"`...)
+	case math.IsInf(val, -1):
+		return append(dst, `"-Inf"`...)
+	}
+
+	return strconv.AppendFloat(dst, val, 'f', -1, bitSize)
+}
+
+// appendTime appends time in TimestampFormat into buffer.
+func appendTime(dst []byte, val time.Time) []byte {
+	dst = append(dst, '"')
+	dst = val.AppendFormat(dst, TimestampFormat)
+	return append(dst, '"')
+}
+
+// appendDur appends duration in milliseconds into buffer.
+func appendDur(dst []byte, val time.Duration) []byte {
+	return strconv.AppendFloat(dst, float64(val)/float64(time.Millisecond), 'f', -1, 64)
+}
+
+// appendJsonString appends the quoted and escaped string into buffer.
+func appendJsonString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		b := s[i]
+		if b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			// replace invalid utf-8 bytes
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+
+	return append(dst, '"')
+}
@@ -15,6 +15,10 @@
 package slago
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
 	"sync"
 	"time"
 )
@@ -54,6 +58,10 @@ func (r *noopRecord) Err(_ error) Record {
 	return r
 }
 
+func (r *noopRecord) Stack() Record {
+	return r
+}
+
 func (r *noopRecord) Errs(_ string, _ []error) Record {
 	return r
 }
@@ -178,10 +186,78 @@ func (r *noopRecord) Durs(_ string, _ []time.Duration) Record {
 	return r
 }
 
+func (r *noopRecord) RawJSON(_ string, _ []byte) Record {
+	return r
+}
+
+func (r *noopRecord) Stringer(_ string, _ fmt.Stringer) Record {
+	return r
+}
+
+func (r *noopRecord) IPAddr(_ string, _ net.IP) Record {
+	return r
+}
+
+func (r *noopRecord) IPPrefix(_ string, _ net.IPNet) Record {
+	return r
+}
+
+func (r *noopRecord) MACAddr(_ string, _ net.HardwareAddr) Record {
+	return r
+}
+
+func (r *noopRecord) URL(_ string, _ *url.URL) Record {
+	return r
+}
+
+func (r *noopRecord) Dict(_ string, _ func(r Record)) Record {
+	return r
+}
+
+func (r *noopRecord) Object(_ string, _ ObjectMarshaler) Record {
+	return r
+}
+
+func (r *noopRecord) Array(_ string, _ ArrayMarshaler) Record {
+	return r
+}
+
 func (r *noopRecord) Interface(_ string, _ interface{}) Record {
 	return r
 }
 
+func (r *noopRecord) Any(_ string, _ interface{}) Record {
+	return r
+}
+
+func (r *noopRecord) Fields(_ map[string]interface{}) Record {
+	return r
+}
+
+func (r *noopRecord) Marker(_ ...string) Record {
+	return r
+}
+
+func (r *noopRecord) Ctx(_ context.Context) Record {
+	return r
+}
+
+func (r *noopRecord) Func(_ string, _ func() interface{}) Record {
+	return r
+}
+
+func (r *noopRecord) Lazy(_ func(r Record)) Record {
+	return r
+}
+
+func (r *noopRecord) Enabled() bool {
+	return false
+}
+
+func (r *noopRecord) Timestamp(_ time.Time) Record {
+	return r
+}
+
 func (r *noopRecord) Msg(_ ...string) {
 	recordPool.Put(r)
 }
@@ -189,3 +265,7 @@ func (r *noopRecord) Msg(_ ...string) {
 func (r *noopRecord) Msgf(_ string, _ ...interface{}) {
 	recordPool.Put(r)
 }
+
+func (r *noopRecord) Msgt(_ string, _ ...interface{}) {
+	recordPool.Put(r)
+}
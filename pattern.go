@@ -70,6 +70,13 @@ func (p *patternParser) Parse() (*node, error) {
 		case "#":
 			if !keywordStart {
 				keywordStart = true
+			} else if !compositeStart && !optionStart && len(keyword) != 0 {
+				// another keyword follows current keyword directly
+				p.appendNode(&node{
+					_type: typeSingle,
+					value: keyword,
+				})
+				keyword = ""
 			} else {
 				buf.WriteString(value)
 			}
@@ -16,8 +16,11 @@ package slago
 
 import (
 	"bytes"
+	"fmt"
 	"strconv"
 	"sync"
+
+	"github.com/buger/jsonparser"
 )
 
 const (
@@ -44,16 +47,6 @@ var (
 		"cyanbr":    colorBrightCyan,
 		"whitebr":   colorBrightWhite,
 	}
-
-	levelColorMap = map[Level]int{
-		TraceLevel: colorWhite,
-		DebugLevel: colorBlue,
-		InfoLevel:  colorGreen,
-		WarnLevel:  colorYellow,
-		ErrorLevel: colorRed,
-		FatalLevel: colorRed,
-		PanicLevel: colorRed,
-	}
 )
 
 // patternEncoder encodes logging event with pattern.
@@ -68,8 +61,20 @@ type PatternEncoderOption struct {
 	Converters map[string]NewConverter
 }
 
-// NewPatternEncoder creates a new instance of pattern encoder.
+// NewPatternEncoder creates a new instance of pattern encoder. It will exit
+// if the layout is invalid, use NewPatternEncoderE if the error is concerned.
 func NewPatternEncoder(options ...func(*PatternEncoderOption)) Encoder {
+	encoder, err := NewPatternEncoderE(options...)
+	if err != nil {
+		ReportfExit("%v", err)
+	}
+
+	return encoder
+}
+
+// NewPatternEncoderE creates a new instance of pattern encoder, an error will
+// be returned if the layout is invalid.
+func NewPatternEncoderE(options ...func(*PatternEncoderOption)) (Encoder, error) {
 	opts := &PatternEncoderOption{}
 	for _, f := range options {
 		f(opts)
@@ -83,29 +88,37 @@ func NewPatternEncoder(options ...func(*PatternEncoderOption)) Encoder {
 	patternParser := NewPatternParser(layout)
 	node, err := patternParser.Parse()
 	if err != nil {
-		ReportfExit("parse pattern error, %v", err)
+		return nil, fmt.Errorf("parse pattern error, %v", err)
 	}
 
 	converters := map[string]NewConverter{
-		"color":   newColorConverter,
-		"level":   newLevelConverter,
-		"date":    newLogDateConverter,
-		"logger":  newLoggerConverter,
-		"message": newMessageConverter,
-		"fields":  newFieldsConverter,
+		"color":     newColorConverter,
+		"level":     newLevelConverter,
+		"date":      newLogDateConverter,
+		"logger":    newLoggerConverter,
+		"message":   newMessageConverter,
+		"fields":    newFieldsConverter,
+		"caller":    newCallerConverter,
+		"file":      newFileConverter,
+		"line":      newLineConverter,
+		"func":      newFuncConverter,
+		"exception": newExceptionConverter,
+		"stack":     newExceptionConverter,
+		"cause":     newCauseConverter,
+		"marker":    newMarkerConverter,
 	}
 	for k, c := range opts.Converters {
 		converters[k] = c
 	}
 	converter, err := NewPatternCompiler(node, converters).Compile()
 	if err != nil {
-		ReportfExit("compile pattern error, %v", err)
+		return nil, fmt.Errorf("compile pattern error, %v", err)
 	}
 
 	return &patternEncoder{
 		buf:       new(bytes.Buffer),
 		converter: converter,
-	}
+	}, nil
 }
 
 func (pe *patternEncoder) Encode(e *LogEvent) (data []byte, err error) {
@@ -169,12 +182,7 @@ func (cc *colorConverter) Convert(origin interface{}, buf *bytes.Buffer) {
 	for c := cc.child; c != nil; c = c.Next() {
 		switch c.(type) {
 		case *levelConverter:
-			color, ok := levelColorMap[level]
-			if !ok {
-				color = colorWhite
-			}
-
-			cc.writeColor(color)
+			cc.writeColor(levelColor(level))
 			c.Convert(origin, cc.buf)
 			cc.writeColorEnd()
 
@@ -428,14 +436,357 @@ func (fc *fieldsConverter) Convert(origin interface{}, buf *bytes.Buffer) {
 		return
 	}
 
-	_ = e.Fields(func(k, v []byte, isString bool) error {
+	var written bool
+	_ = e.FlatFields(func(k, v []byte, isString bool) error {
 		buf.Write(k)
 		buf.WriteString("=")
 		buf.Write(v)
 		buf.WriteByte(' ')
+		written = true
 		return nil
 	})
 
 	// remove last space
-	buf.Truncate(buf.Len() - 1)
+	if written {
+		buf.Truncate(buf.Len() - 1)
+	}
+}
+
+type callerConverter struct {
+	next Converter
+	full bool
+}
+
+func newCallerConverter() Converter {
+	return &callerConverter{}
+}
+
+func (cc *callerConverter) AttatchNext(next Converter) {
+	cc.next = next
+}
+
+func (cc *callerConverter) Next() Converter {
+	return cc.next
+}
+
+func (cc *callerConverter) AttachChild(_ Converter) {
+}
+
+func (cc *callerConverter) AttachOptions(opts []string) {
+	if len(opts) != 0 && opts[0] == "full" {
+		cc.full = true
+	}
+}
+
+func (cc *callerConverter) Convert(origin interface{}, buf *bytes.Buffer) {
+	e, ok := origin.(*LogEvent)
+	if !ok {
+		buf.WriteByte('-')
+		return
+	}
+
+	_, file, line := parseFrame(e.Caller())
+	if len(file) == 0 {
+		buf.WriteByte('-')
+		return
+	}
+
+	if !cc.full {
+		file = file[bytes.LastIndexByte(file, '/')+1:]
+	}
+	buf.Write(file)
+	if len(line) != 0 {
+		buf.WriteByte(':')
+		buf.Write(line)
+	}
+}
+
+type fileConverter struct {
+	next Converter
+}
+
+func newFileConverter() Converter {
+	return &fileConverter{}
+}
+
+func (fc *fileConverter) AttatchNext(next Converter) {
+	fc.next = next
+}
+
+func (fc *fileConverter) Next() Converter {
+	return fc.next
+}
+
+func (fc *fileConverter) AttachChild(_ Converter) {
+}
+
+func (fc *fileConverter) AttachOptions(_ []string) {
+}
+
+func (fc *fileConverter) Convert(origin interface{}, buf *bytes.Buffer) {
+	e, ok := origin.(*LogEvent)
+	if !ok {
+		buf.WriteByte('-')
+		return
+	}
+
+	_, file, _ := parseFrame(e.Caller())
+	if len(file) == 0 {
+		buf.WriteByte('-')
+		return
+	}
+
+	buf.Write(file[bytes.LastIndexByte(file, '/')+1:])
+}
+
+type lineConverter struct {
+	next Converter
+}
+
+func newLineConverter() Converter {
+	return &lineConverter{}
+}
+
+func (lc *lineConverter) AttatchNext(next Converter) {
+	lc.next = next
+}
+
+func (lc *lineConverter) Next() Converter {
+	return lc.next
+}
+
+func (lc *lineConverter) AttachChild(_ Converter) {
+}
+
+func (lc *lineConverter) AttachOptions(_ []string) {
+}
+
+func (lc *lineConverter) Convert(origin interface{}, buf *bytes.Buffer) {
+	e, ok := origin.(*LogEvent)
+	if !ok {
+		buf.WriteByte('-')
+		return
+	}
+
+	_, _, line := parseFrame(e.Caller())
+	if len(line) == 0 {
+		buf.WriteByte('-')
+		return
+	}
+
+	buf.Write(line)
+}
+
+type funcConverter struct {
+	next Converter
+}
+
+func newFuncConverter() Converter {
+	return &funcConverter{}
+}
+
+func (fc *funcConverter) AttatchNext(next Converter) {
+	fc.next = next
+}
+
+func (fc *funcConverter) Next() Converter {
+	return fc.next
+}
+
+func (fc *funcConverter) AttachChild(_ Converter) {
+}
+
+func (fc *funcConverter) AttachOptions(_ []string) {
+}
+
+func (fc *funcConverter) Convert(origin interface{}, buf *bytes.Buffer) {
+	e, ok := origin.(*LogEvent)
+	if !ok {
+		buf.WriteByte('-')
+		return
+	}
+
+	fn, _, _ := parseFrame(e.Caller())
+	if len(fn) == 0 {
+		buf.WriteByte('-')
+		return
+	}
+
+	buf.Write(fn)
+}
+
+type exceptionConverter struct {
+	next  Converter
+	depth int
+}
+
+func newExceptionConverter() Converter {
+	return &exceptionConverter{
+		depth: -1,
+	}
+}
+
+func (ec *exceptionConverter) AttatchNext(next Converter) {
+	ec.next = next
+}
+
+func (ec *exceptionConverter) Next() Converter {
+	return ec.next
+}
+
+func (ec *exceptionConverter) AttachChild(_ Converter) {
+}
+
+func (ec *exceptionConverter) AttachOptions(opts []string) {
+	if len(opts) == 0 {
+		return
+	}
+
+	depth, err := strconv.Atoi(opts[0])
+	if err != nil {
+		return
+	}
+
+	ec.depth = depth
+}
+
+func (ec *exceptionConverter) Convert(origin interface{}, buf *bytes.Buffer) {
+	e, ok := origin.(*LogEvent)
+	if !ok {
+		return
+	}
+
+	var count = 0
+	_ = e.Stack(func(frame []byte) error {
+		if ec.depth >= 0 && count >= ec.depth {
+			return errFound
+		}
+		count++
+
+		buf.WriteString("\n\tat ")
+		buf.Write(frame)
+		return nil
+	})
+}
+
+type causeConverter struct {
+	next  Converter
+	depth int
+}
+
+func newCauseConverter() Converter {
+	return &causeConverter{
+		depth: -1,
+	}
+}
+
+func (cc *causeConverter) AttatchNext(next Converter) {
+	cc.next = next
+}
+
+func (cc *causeConverter) Next() Converter {
+	return cc.next
+}
+
+func (cc *causeConverter) AttachChild(_ Converter) {
+}
+
+func (cc *causeConverter) AttachOptions(opts []string) {
+	if len(opts) == 0 {
+		return
+	}
+
+	depth, err := strconv.Atoi(opts[0])
+	if err != nil {
+		return
+	}
+
+	cc.depth = depth
+}
+
+func (cc *causeConverter) Convert(origin interface{}, buf *bytes.Buffer) {
+	e, ok := origin.(*LogEvent)
+	if !ok {
+		return
+	}
+
+	_ = e.Fields(func(k, v []byte, isString bool) error {
+		if string(k) != ErrorFieldKey {
+			return nil
+		}
+
+		if isString {
+			// the error may be a plain string from bridged loggers
+			msg, _ := jsonparser.ParseString(v)
+			buf.WriteByte('\n')
+			buf.WriteString(msg)
+		} else {
+			var count = 0
+			cc.writeError(v, "", &count, buf)
+		}
+
+		return errFound
+	})
+}
+
+func (cc *causeConverter) writeError(data []byte, prefix string, count *int, buf *bytes.Buffer) {
+	errType, _ := jsonparser.GetString(data, errorTypeKey)
+	msg, _ := jsonparser.GetString(data, errorMessageKey)
+	buf.WriteByte('\n')
+	buf.WriteString(prefix)
+	buf.WriteString(errType)
+	buf.WriteString(": ")
+	buf.WriteString(msg)
+
+	_, _ = jsonparser.ArrayEach(data, func(value []byte, _ jsonparser.ValueType, _ int, _ error) {
+		if cc.depth >= 0 && *count >= cc.depth {
+			return
+		}
+		*count++
+
+		cc.writeError(value, "Caused by: ", count, buf)
+	}, errorCausesKey)
+}
+
+type markerConverter struct {
+	next Converter
+}
+
+func newMarkerConverter() Converter {
+	return &markerConverter{}
+}
+
+func (mc *markerConverter) AttatchNext(next Converter) {
+	mc.next = next
+}
+
+func (mc *markerConverter) Next() Converter {
+	return mc.next
+}
+
+func (mc *markerConverter) AttachChild(_ Converter) {
+}
+
+func (mc *markerConverter) AttachOptions(_ []string) {
+}
+
+func (mc *markerConverter) Convert(origin interface{}, buf *bytes.Buffer) {
+	e, ok := origin.(*LogEvent)
+	if !ok {
+		return
+	}
+
+	var count = 0
+	_ = e.Markers(func(marker []byte) error {
+		if count > 0 {
+			buf.WriteByte(',')
+		}
+		count++
+		buf.Write(marker)
+		return nil
+	})
+
+	if count == 0 {
+		buf.WriteByte('-')
+	}
 }
@@ -15,6 +15,10 @@
 package slago
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
 	"time"
 )
 
@@ -35,6 +39,9 @@ type Record interface {
 	// Err adds err to this record.
 	Err(err error) Record
 
+	// Stack adds stack of current goroutine to this record.
+	Stack() Record
+
 	// Errs adds err array to this record.
 	Errs(key string, errs []error) Record
 
@@ -128,12 +135,71 @@ type Record interface {
 	// Time adds duration array value to this record.
 	Durs(key string, val []time.Duration) Record
 
+	// RawJSON adds pre-encoded json to this record, it will not be quoted.
+	RawJSON(key string, val []byte) Record
+
+	// Stringer adds value of fmt.Stringer to this record.
+	Stringer(key string, val fmt.Stringer) Record
+
+	// IPAddr adds IP address to this record.
+	IPAddr(key string, ip net.IP) Record
+
+	// IPPrefix adds IP prefix (CIDR) to this record.
+	IPPrefix(key string, pfx net.IPNet) Record
+
+	// MACAddr adds hardware address to this record.
+	MACAddr(key string, ha net.HardwareAddr) Record
+
+	// URL adds url to this record.
+	URL(key string, val *url.URL) Record
+
+	// Dict adds a nested object built by the given function to this record.
+	Dict(key string, f func(r Record)) Record
+
+	// Object adds a nested object encoded by ObjectMarshaler to this record.
+	Object(key string, obj ObjectMarshaler) Record
+
+	// Array adds an array encoded by ArrayMarshaler to this record.
+	Array(key string, arr ArrayMarshaler) Record
+
 	// Interface adds interface value to this record.
 	Interface(key string, val interface{}) Record
 
+	// Any adds value to this record with the typed method matching its type.
+	Any(key string, val interface{}) Record
+
+	// Fields adds all the fields in map to this record.
+	Fields(fields map[string]interface{}) Record
+
+	// Marker adds markers to this record, which can be used to tag and filter logs.
+	Marker(names ...string) Record
+
+	// Ctx adds fields extracted from context to this record.
+	Ctx(ctx context.Context) Record
+
+	// Func adds value returned by the given function to this record. The
+	// function will be called only when this record is enabled.
+	Func(key string, f func() interface{}) Record
+
+	// Lazy adds fields with the given function, which will be called only
+	// when this record is enabled.
+	Lazy(f func(r Record)) Record
+
+	// Enabled checks if this record will be written.
+	Enabled() bool
+
+	// Timestamp sets the event time of this record instead of current time.
+	Timestamp(t time.Time) Record
+
 	// Msg adds a message to this record and output log.
 	Msg(msg... string)
 
 	// Msgf adds a message with format to this record and output log.
 	Msgf(format string, v ...interface{})
+
+	// Msgt adds a message rendered from template with named placeholders,
+	// such as "user {user} logged in", to this record and output log. Each
+	// placeholder will be added as a field and the template will be added
+	// as message_template.
+	Msgt(template string, args ...interface{})
 }
@@ -0,0 +1,101 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"reflect"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/buger/jsonparser"
+)
+
+var (
+	// droppedEvents is the number of recursive events dropped.
+	droppedEvents int64
+
+	// the entries of functions in which events are written with guard entered
+	multiWriterEntry uintptr
+	asyncWriterEntry uintptr
+)
+
+func init() {
+	// the functions refer to the entries, so they can't be initialized in declaration
+	multiWriterEntry = funcEntry((*MultiWriter).Write)
+	asyncWriterEntry = funcEntry((*asyncWriter).write)
+}
+
+// DroppedEvents gets the number of events dropped since they were emitted
+// inside writers on the same goroutine.
+func DroppedEvents() int64 {
+	return atomic.LoadInt64(&droppedEvents)
+}
+
+// writingGuard guards a writer against the events emitted inside itself. The
+// writer marks writing with the guard, and the events emitted by the goroutine
+// which is writing will be treated as recursive.
+type writingGuard struct {
+	writing int32
+}
+
+// enter marks the start of writing an event.
+func (g *writingGuard) enter() {
+	atomic.AddInt32(&g.writing, 1)
+}
+
+// exit marks the end of writing an event.
+func (g *writingGuard) exit() {
+	atomic.AddInt32(&g.writing, -1)
+}
+
+// recursive reports whether the current goroutine is writing with the guard
+// entered in the function with given entry. The stack of current goroutine is
+// only inspected when the guarded writer is writing, which means the event
+// would wait for the writer anyway.
+func (g *writingGuard) recursive(entry uintptr) bool {
+	if atomic.LoadInt32(&g.writing) == 0 {
+		return false
+	}
+
+	var pcs [32]uintptr
+	// skip runtime.Callers, recursive and the guarded function itself
+	skip := 3
+	for {
+		n := runtime.Callers(skip, pcs[:])
+		for _, pc := range pcs[:n] {
+			// the entry is the outermost function if pc is in inlined function
+			if f := runtime.FuncForPC(pc - 1); f != nil && f.Entry() == entry {
+				return true
+			}
+		}
+		if n < len(pcs) {
+			return false
+		}
+		skip += n
+	}
+}
+
+// funcEntry gets the entry of the given function.
+func funcEntry(fn interface{}) uintptr {
+	return runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Entry()
+}
+
+// dropRecursive drops the recursive event and diverts it to the status manager.
+func dropRecursive(p []byte) {
+	atomic.AddInt64(&droppedEvents, 1)
+	lvl, _ := jsonparser.GetString(p, LevelFieldKey)
+	msg, _ := jsonparser.GetString(p, MessageFieldKey)
+	defaultStatusManager.Warn("writer", "recursive event dropped: [%s] %s", lvl, msg)
+}
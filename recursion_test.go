@@ -0,0 +1,124 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRecursion(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "recursion test")
+}
+
+var _ = Describe("recursion", func() {
+	It("drop recursive events", func() {
+		buf := &bytes.Buffer{}
+		mw := NewMultiWriter()
+		mw.AddWriter(&recursiveWriter{mw: mw, buf: buf})
+		dropped := DroppedEvents()
+
+		_, _ = mw.Write([]byte(`{"message":"outer"}`))
+		Expect(buf.String()).To(Equal(`{"message":"outer"}`))
+		Expect(DroppedEvents()).To(Equal(dropped + 1))
+	})
+	It("drop recursive events in deep stack", func() {
+		buf := &bytes.Buffer{}
+		mw := NewMultiWriter()
+		mw.AddWriter(&recursiveWriter{mw: mw, buf: buf, depth: 100})
+		dropped := DroppedEvents()
+
+		_, _ = mw.Write([]byte(`{"message":"outer"}`))
+		Expect(buf.String()).To(Equal(`{"message":"outer"}`))
+		Expect(DroppedEvents()).To(Equal(dropped + 1))
+	})
+	It("drop recursive events of async writer", func() {
+		buf := &bytes.Buffer{}
+		mw := NewMultiWriter()
+		async := NewAsyncWriter(func(o *AsyncWriterOption) {
+			o.Ref = &recursiveWriter{mw: mw, buf: buf}
+		})
+		mw.AddWriter(async)
+		defer mw.Reset()
+		dropped := DroppedEvents()
+
+		_, _ = mw.Write([]byte(`{"message":"outer"}`))
+		Eventually(DroppedEvents).Should(Equal(dropped + 1))
+		Consistently(DroppedEvents, "50ms").Should(Equal(dropped + 1))
+	})
+	It("write concurrently", func() {
+		buf := &bytes.Buffer{}
+		mw := NewMultiWriter()
+		// the slow writer makes the events wait for each other
+		mw.AddWriter(&slowWriter{bufferWriter{buf}})
+		dropped := DroppedEvents()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = mw.Write([]byte(`{}`))
+			}()
+		}
+		wg.Wait()
+		Expect(buf.Len()).To(Equal(20))
+		Expect(DroppedEvents()).To(Equal(dropped))
+	})
+})
+
+// recursiveWriter logs into the multi writer it belongs to with the stack
+// deeper than the given depth.
+type recursiveWriter struct {
+	mw    *MultiWriter
+	buf   *bytes.Buffer
+	depth int
+}
+
+func (w *recursiveWriter) Write(p []byte) (n int, err error) {
+	w.log(w.depth)
+	return w.buf.Write(p)
+}
+
+func (w *recursiveWriter) log(depth int) {
+	if depth > 0 {
+		w.log(depth - 1)
+		return
+	}
+	_, _ = w.mw.Write([]byte(`{"level":"ERROR","message":"inner"}`))
+}
+
+func (w *recursiveWriter) Encoder() Encoder {
+	return nil
+}
+
+func (w *recursiveWriter) Filter() Filter {
+	return nil
+}
+
+type slowWriter struct {
+	bufferWriter
+}
+
+func (w *slowWriter) Write(p []byte) (n int, err error) {
+	time.Sleep(time.Millisecond)
+	return w.bufferWriter.Write(p)
+}
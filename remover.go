@@ -76,7 +76,9 @@ func (r *timeBasedArchiveRemover) cleanAsync(now time.Time,
 
 			// remove all log files
 			for _, fn := range files {
-				_ = os.Remove(fn)
+				if err := os.Remove(fn); err != nil && !os.IsNotExist(err) {
+					defaultStatusManager.Warn("archive remover", "remove file error: %v", err)
+				}
 			}
 
 			// remove parent directory
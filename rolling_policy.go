@@ -83,8 +83,20 @@ type TimeBasedRPOption struct {
 }
 
 // NewTimeBasedRollingPolicy creates a instance of time based rolling policy
-// for file writer.
+// for file writer. It will exit if the filename pattern is invalid, use
+// NewTimeBasedRollingPolicyE if the error is concerned.
 func NewTimeBasedRollingPolicy(options ...func(*TimeBasedRPOption)) RollingPolicy {
+	rp, err := NewTimeBasedRollingPolicyE(options...)
+	if err != nil {
+		ReportfExit("%v", err)
+	}
+
+	return rp
+}
+
+// NewTimeBasedRollingPolicyE creates a instance of time based rolling policy
+// for file writer, an error will be returned if the filename pattern is invalid.
+func NewTimeBasedRollingPolicyE(options ...func(*TimeBasedRPOption)) (RollingPolicy, error) {
 	opt := &TimeBasedRPOption{
 		FilenamePattern: "slago-archive.#date{2006-01-02}.log",
 	}
@@ -95,14 +107,13 @@ func NewTimeBasedRollingPolicy(options ...func(*TimeBasedRPOption)) RollingPolic
 
 	fp, err := newFilenamePattern(opt.FilenamePattern)
 	if err != nil {
-		ReportfExit("create rolling policy error: %v\n", err)
-		return nil
+		return nil, fmt.Errorf("create rolling policy error: %v", err)
 	}
 
 	return &timeBasedRollingPolicy{
 		maxHistory:      opt.MaxHistory,
 		filenamePattern: fp,
-	}
+	}, nil
 }
 
 func (rp *timeBasedRollingPolicy) Prepare() error {
@@ -183,9 +194,23 @@ type SizeAndTimeBasedRPOption struct {
 }
 
 // NewSizeAndTimeBasedRollingPolicy creates a new instance of size and time
-// based rolling policy for file writer.
+// based rolling policy for file writer. It will exit if the options are invalid,
+// use NewSizeAndTimeBasedRollingPolicyE if the error is concerned.
 func NewSizeAndTimeBasedRollingPolicy(options ...func(
 	*SizeAndTimeBasedRPOption)) RollingPolicy {
+	rp, err := NewSizeAndTimeBasedRollingPolicyE(options...)
+	if err != nil {
+		ReportfExit("%v", err)
+	}
+
+	return rp
+}
+
+// NewSizeAndTimeBasedRollingPolicyE creates a new instance of size and time
+// based rolling policy for file writer, an error will be returned if the
+// options are invalid.
+func NewSizeAndTimeBasedRollingPolicyE(options ...func(
+	*SizeAndTimeBasedRPOption)) (RollingPolicy, error) {
 	opt := &SizeAndTimeBasedRPOption{
 		MaxFileSize:     "128MB",
 		FilenamePattern: "slago-archive.#date{2006-01-02}.#index.log",
@@ -197,17 +222,21 @@ func NewSizeAndTimeBasedRollingPolicy(options ...func(
 
 	fileSize, err := parseFileSize(opt.MaxFileSize)
 	if err != nil {
-		ReportfExit("parse file size error: %v", err)
+		return nil, fmt.Errorf("parse file size error: %v", err)
 	}
 
-	tbrp := NewTimeBasedRollingPolicy(func(o *TimeBasedRPOption) {
+	tbrp, err := NewTimeBasedRollingPolicyE(func(o *TimeBasedRPOption) {
 		o.FilenamePattern = opt.FilenamePattern
 		o.MaxHistory = opt.MaxHistory
-	}).(*timeBasedRollingPolicy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &sizeAndTimeBasedRollingPolicy{
-		timeBasedRollingPolicy: tbrp,
+		timeBasedRollingPolicy: tbrp.(*timeBasedRollingPolicy),
 		triggerSize:            fileSize,
-	}
+	}, nil
 }
 
 func (rp *sizeAndTimeBasedRollingPolicy) Prepare() error {
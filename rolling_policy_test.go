@@ -15,6 +15,7 @@
 package slago
 
 import (
+	"os"
 	"testing"
 	"time"
 
@@ -59,3 +60,27 @@ var _ = Describe("size and time based rolling policy", func() {
 		Expect(result).To(BeTrue())
 	})
 })
+var _ = Describe("rolling policy with error", func() {
+	It("invalid options", func() {
+		_, err := NewTimeBasedRollingPolicyE(func(o *TimeBasedRPOption) {
+			o.FilenamePattern = "slago.#unknown.log"
+		})
+		Expect(err).NotTo(BeNil())
+		_, err = NewSizeAndTimeBasedRollingPolicyE(func(o *SizeAndTimeBasedRPOption) {
+			o.MaxFileSize = "10XB"
+		})
+		Expect(err).NotTo(BeNil())
+		_, err = NewSizeAndTimeBasedRollingPolicyE()
+		Expect(err).To(BeNil())
+	})
+	It("file writer start error", func() {
+		fw := NewFileWriter(func(o *FileWriterOption) {
+			o.Filename = "slago-test.log"
+			o.RollingPolicy, _ = NewTimeBasedRollingPolicyE(func(o *TimeBasedRPOption) {
+				o.FilenamePattern = "slago-archive.log"
+			})
+		})
+		defer os.Remove("slago-test.log")
+		Expect(fw.(Lifecycle).Start()).NotTo(BeNil())
+	})
+})
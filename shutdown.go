@@ -0,0 +1,131 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ShutdownTimeout is the timeout to shut down logger contexts before fatal
+// and panic records terminate the process.
+var ShutdownTimeout = 5 * time.Second
+
+const drainCheckInterval = 5 * time.Millisecond
+
+// Drainer represents a writer which buffers events, the buffered events
+// can be drained before the writer stops.
+type Drainer interface {
+	// Drain waits until all the buffered events are written or the context is done.
+	Drain(ctx context.Context) error
+}
+
+var (
+	contextsLocker sync.Mutex
+	activeContexts = make(map[*LoggerContext]bool)
+)
+
+// Shutdown shuts down the default logger context.
+func Shutdown(ctx context.Context) error {
+	return defaultContext.Shutdown(ctx)
+}
+
+// ShutdownBeforeExit shuts down all the active logger contexts within
+// ShutdownTimeout. It is called by slago logger implementations before
+// fatal and panic records terminate the process.
+func ShutdownBeforeExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+
+	contextsLocker.Lock()
+	contexts := make([]*LoggerContext, 0, len(activeContexts))
+	for lc := range activeContexts {
+		contexts = append(contexts, lc)
+	}
+	contextsLocker.Unlock()
+
+	for _, lc := range contexts {
+		_ = lc.Shutdown(ctx)
+	}
+}
+
+// Shutdown stops accepting events, drains the writers buffering events within
+// the deadline of context, then stops all the writers. The context error will
+// be returned if the writers are not drained before the context is done.
+func (lc *LoggerContext) Shutdown(ctx context.Context) error {
+	lc.setWatcher(nil)
+
+	lc.locker.Lock()
+	if lc.cache == nil || atomic.LoadInt32(&lc.shutdown) == 1 {
+		lc.locker.Unlock()
+		return nil
+	}
+	atomic.StoreInt32(&lc.shutdown, 1)
+	loggers := make([]*classicLogger, 0, len(lc.cache))
+	for _, logger := range lc.cache {
+		loggers = append(loggers, logger)
+	}
+	lc.locker.Unlock()
+	deactivateContext(lc)
+
+	writers := make([]Writer, 0)
+	for _, logger := range loggers {
+		writers = append(writers, logger.output.writer.swap(nil)...)
+	}
+
+	var err error
+	for _, w := range writers {
+		if d, ok := w.(Drainer); ok {
+			if e := d.Drain(ctx); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	stopWriters(writers, nil)
+
+	return err
+}
+
+func activateContext(lc *LoggerContext) {
+	contextsLocker.Lock()
+	defer contextsLocker.Unlock()
+
+	activeContexts[lc] = true
+}
+
+func deactivateContext(lc *LoggerContext) {
+	contextsLocker.Lock()
+	defer contextsLocker.Unlock()
+
+	delete(activeContexts, lc)
+}
+
+// drain waits until there's no pending events or the context is done.
+func drain(ctx context.Context, pending *int64) error {
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	for atomic.LoadInt64(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestShutdown(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "shutdown test")
+}
+
+var _ = Describe("shutdown", func() {
+	It("drain and stop", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		buf := &bytes.Buffer{}
+		lc.Logger("acme").AddWriter(NewAsyncWriter(func(o *AsyncWriterOption) {
+			o.Ref = &bufferWriter{buf}
+		}))
+
+		dispatcher := newDispatchWriter(lc, nil)
+		for i := 0; i < 100; i++ {
+			_, _ = dispatcher.Write([]byte(`{"logger_name":"acme"}`))
+		}
+		Expect(lc.Shutdown(context.Background())).To(BeNil())
+		Expect(buf.Len()).To(Equal(2200))
+
+		_, _ = dispatcher.Write([]byte(`{"logger_name":"acme"}`))
+		Expect(buf.Len()).To(Equal(2200))
+		lc.Reset()
+	})
+	It("timeout", func() {
+		lc := NewLoggerContext()
+		lc.Bind(NewNativeLogger())
+		blocker := make(chan struct{})
+		defer close(blocker)
+		lc.Logger().AddWriter(NewAsyncWriter(func(o *AsyncWriterOption) {
+			o.Ref = &blockingWriter{blocker}
+		}))
+
+		_, _ = newDispatchWriter(lc, nil).Write([]byte(`{"logger_name":"ROOT"}`))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		Expect(lc.Shutdown(ctx)).To(Equal(context.DeadlineExceeded))
+	})
+})
+
+var _ = Describe("stop async writer", func() {
+	It("wake up taking", func() {
+		queue := NewBlockingQueue(4)
+		taken := make(chan bool)
+		go func() {
+			_, ok := queue.Take()
+			taken <- ok
+		}()
+		queue.Put([]byte("event"))
+		Expect(<-taken).To(Equal(true))
+
+		go func() {
+			_, ok := queue.Take()
+			taken <- ok
+		}()
+		Expect(queue.Close()).To(Equal(0))
+		Expect(<-taken).To(Equal(false))
+	})
+	It("stop worker", func() {
+		before := runtime.NumGoroutine()
+		buf := &bytes.Buffer{}
+		w := NewAsyncWriter(func(o *AsyncWriterOption) {
+			o.Ref = &bufferWriter{buf}
+		})
+		lc := w.(Lifecycle)
+		for i := 0; i < 10; i++ {
+			Expect(lc.Start()).To(BeNil())
+			_, _ = w.Write([]byte("event"))
+			Expect(w.(*asyncWriter).Drain(context.Background())).To(BeNil())
+			lc.Stop()
+		}
+		Expect(buf.String()).To(Equal(strings.Repeat("event", 10)))
+		Eventually(runtime.NumGoroutine).Should(BeNumerically("<=", before))
+	})
+})
+
+type blockingWriter struct {
+	blocker chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (n int, err error) {
+	<-w.blocker
+	return len(p), nil
+}
+
+func (w *blockingWriter) Encoder() Encoder {
+	return nil
+}
+
+func (w *blockingWriter) Filter() Filter {
+	return nil
+}
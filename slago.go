@@ -15,36 +15,11 @@
 package slago
 
 import (
-	"strings"
-	"sync"
-)
-
-const (
-	TraceLevel Level = iota
-	DebugLevel
-	InfoLevel
-	WarnLevel
-	ErrorLevel
-	FatalLevel
-	PanicLevel
+	"context"
 )
 
 const RootLoggerName = "ROOT"
 
-var (
-	levelMap = map[string]Level{
-		"TRACE":  TraceLevel,
-		"DEBUG":  DebugLevel,
-		"INFO":   InfoLevel,
-		"WARN":   WarnLevel,
-		"ERROR":  ErrorLevel,
-		"FALTAL": FatalLevel,
-		"PANIC":  PanicLevel,
-	}
-)
-
-type Level int8
-
 // SlaLogger represents a logging abstraction.
 type SlaLogger interface {
 	// Name returns the name of current slago logger implementation.
@@ -59,6 +34,12 @@ type SlaLogger interface {
 	// SetLevel sets global level for root logger.
 	SetLevel(lvl Level)
 
+	// With creates a context to add fields which will be bound to a child logger.
+	With() *FieldContext
+
+	// Ctx creates a child logger with fields extracted from context.
+	Ctx(ctx context.Context) SlaLogger
+
 	// Trace logs with trace level.
 	Trace() Record
 
@@ -68,22 +49,38 @@ type SlaLogger interface {
 	// Info logs with info level.
 	Info() Record
 
+	// Notice logs with notice level.
+	Notice() Record
+
 	// Warn logs with warn level.
 	Warn() Record
 
 	// Error logs with error level.
 	Error() Record
 
-	// Fatal logs with faltal level.
+	// Fatal logs with fatal level.
 	Fatal() Record
 
 	// Panic logs with panic level.
 	Panic() Record
 
+	// Level logs with the given level, which can be a custom level.
+	Level(lvl Level) Record
+
 	// WriteRaw writes raw logging event.
 	WriteRaw(p []byte)
 }
 
+// OutputLogger represents a slago logger which can create a logger writing into
+// another writer. The events of named loggers are written into their own writers
+// directly if the bound slago logger implements it, otherwise the events will be
+// dispatched with the logger name in events.
+type OutputLogger interface {
+	// Output creates a logger with the same level and fields, which writes into
+	// the given writer instead of the writers of current logger.
+	Output(w Writer) SlaLogger
+}
+
 // Bridge represents bridge between other logging framework and slago logger.
 type Bridge interface {
 	// Name returns the name of this bridge.
@@ -93,40 +90,16 @@ type Bridge interface {
 	ParseLevel(lvl string) Level
 }
 
-var (
-	loggers = make([]SlaLogger, 0)
-	bridges = make([]Bridge, 0)
+var defaultContext = NewLoggerContext()
 
-	onceLogger   sync.Once
-	loggerLocker sync.Mutex
-	loggerCache  map[string]SlaLogger
-)
+// DefaultContext gets the default logger context used by package level functions.
+func DefaultContext() *LoggerContext {
+	return defaultContext
+}
 
 // Logger get a global slago logger to use. The name will only get the first one.
 func Logger(name ...string) SlaLogger {
-	onceLogger.Do(func() {
-		loggerLen := len(loggers)
-		if loggerLen > 1 {
-			Report("multiple slago logger implementation found")
-		} else if loggerLen == 0 {
-			Bind(newNoopLogger())
-			Report("no slago logger found, default to " +
-				"no-operation (NOOP) logger implementation")
-		}
-		logger := loggers[0]
-
-		for _, b := range bridges {
-			if logger.Name() == b.Name() {
-				ReportfExit("cycle logger checked, %s -> slago -> %s",
-					b.Name(), logger.Name())
-			}
-		}
-
-		loggerCache = make(map[string]SlaLogger)
-		loggerCache[RootLoggerName] = logger
-	})
-
-	return findLogger(name...)
+	return defaultContext.Logger(name...)
 }
 
 // LoggerC get a global slago logger with a caller package name.
@@ -136,83 +109,52 @@ func LoggerC() SlaLogger {
 	return Logger(pkgName)
 }
 
-func findLogger(name ...string) SlaLogger {
-	loggerLocker.Lock()
-	defer loggerLocker.Unlock()
-
-	rootLogger := loggerCache[RootLoggerName]
-	var realName string
-	if len(name) > 0 {
-		realName = name[0]
-	}
-
-	child, ok := loggerCache[realName]
-	if ok {
-		return child
-	}
-
-	var i = 0
-	var logger = rootLogger
-	var childName string
-	for {
-		index := indexOfSlash(realName, i)
-		if index == -1 {
-			childName = realName
-		} else {
-			childName = realName[:index]
-		}
-		i = index + 1
-		child, ok = loggerCache[childName]
-		if !ok {
-			child = newClassicLogger(childName, rootLogger, logger)
-			loggerCache[childName] = child
-		}
-		logger = child
-
-		if index == -1 {
-			return child
-		}
-	}
+// SetAdditive sets additivity of the logger with given name. If the logger is
+// not additive, the events of this logger and its children won't be written into
+// the writers of ancestors.
+func SetAdditive(name string, additive bool) {
+	defaultContext.SetAdditive(name, additive)
 }
 
-// Bind binds an implementation of slago logger as output logger.
-func Bind(logger SlaLogger) {
-	loggers = append(loggers, logger)
+// Loggers gets level information of all the named loggers sorted by name.
+func Loggers() []LoggerLevel {
+	return defaultContext.Loggers()
 }
 
-// Install installs a logging framework bridge into slago. All the log of the bridge
-// will be delegated to slagto if the logging framework bridge was installed.
-func Install(bridge Bridge) {
-	bridges = append(bridges, bridge)
+// EffectiveLevel gets the level of the logger with given name. The level is
+// inherited from the nearest ancestor if it's not set in this logger.
+func EffectiveLevel(name string) Level {
+	return defaultContext.EffectiveLevel(name)
+}
+
+// ResetLevel resets the level of the logger with given name, then the level
+// will be inherited from the nearest ancestor.
+func ResetLevel(name string) {
+	defaultContext.ResetLevel(name)
 }
 
-func (l Level) String() string {
-	switch l {
-	case DebugLevel:
-		return "DEBUG"
-	case InfoLevel:
-		return "INFO"
-	case WarnLevel:
-		return "WARN"
-	case ErrorLevel:
-		return "ERROR"
-	case FatalLevel:
-		return "FALTAL"
-	case PanicLevel:
-		return "PANIC"
-	case TraceLevel:
-		fallthrough
-	default:
-		return "TRACE"
-	}
+// Bind binds an implementation of slago logger as output logger. An error will
+// be returned if the binder conflicts with installed bridges, or the binder has
+// been bound to another context.
+func Bind(logger SlaLogger) error {
+	return defaultContext.Bind(logger)
 }
 
-// ParseLevel converts a level string into slago level value.
-func ParseLevel(lvl string) Level {
-	level, ok := levelMap[strings.ToUpper(lvl)]
-	if !ok {
-		return TraceLevel
-	}
+// UseBinder selects the binder with given name when multiple binders are bound.
+// The name can be the full name of binder or the last element of it, such as
+// go.uber.org/zap or zap. SLAGO_BINDER environment variable overrides it.
+func UseBinder(name string) error {
+	return defaultContext.UseBinder(name)
+}
 
-	return level
+// Binders gets the names of all bound binders.
+func Binders() []string {
+	return defaultContext.Binders()
+}
+
+// Install installs a logging framework bridge into slago. All the log of the bridge
+// will be delegated to slagto if the logging framework bridge was installed. An error
+// will be returned if the bridge conflicts with any bound binder.
+func Install(bridge Bridge) error {
+	return defaultContext.Install(bridge)
 }
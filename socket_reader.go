@@ -53,14 +53,15 @@ func NewSocketReader(options ...func(*SocketReaderOption)) *SocketReader {
 	}
 }
 
-func (sr *SocketReader) Start() {
+// Start starts the socket reader, it will block until the server stops.
+func (sr *SocketReader) Start() error {
 	sr.locker.Lock()
 	sr.isRunning = true
 	sr.locker.Unlock()
 
 	Logger().Info().Msgf("socket reader is listening on %v with path %v", sr.port, sr.path)
 	http.HandleFunc(sr.path, sr.readLog)
-	fmt.Print(http.ListenAndServe(fmt.Sprintf(":%v", sr.port), nil))
+	return http.ListenAndServe(fmt.Sprintf(":%v", sr.port), nil)
 }
 
 func (sr *SocketReader) Stop() {
@@ -72,32 +73,35 @@ func (sr *SocketReader) Stop() {
 func (sr *SocketReader) readLog(w http.ResponseWriter, r *http.Request) {
 	conn, err := sr.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		Logger().Error().Err(err).Msg("read log upgrade error")
+		defaultStatusManager.Error("socket reader", "upgrade error: %v", err)
+		return
 	}
 	defer func() {
 		_ = conn.Close()
 	}()
 
-	Logger().Info().Msgf("socket reader got a client connected: %v", r.Host)
+	// the statuses of reader are not logged, since the events may be sent
+	// back to this reader by socket writer
+	defaultStatusManager.Info("socket reader", "client connected: %v", r.Host)
 
 	for {
 		if !sr.isRunning {
-			Logger().Info().Msg("socket log reader stopped, closing...")
+			defaultStatusManager.Info("socket reader", "reader stopped, closing...")
 			break
 		}
 
 		if msgType, data, err := conn.ReadMessage(); err != nil {
 			if websocket.IsCloseError(err,
 				websocket.CloseNormalClosure, websocket.CloseAbnormalClosure) {
-				Logger().Info().Msg("socket client has closed")
+				defaultStatusManager.Info("socket reader", "client has closed")
 				break
 			}
 
-			Logger().Error().Err(err).Msg("read log err")
+			defaultStatusManager.Error("socket reader", "read error: %v", err)
 			continue
 		} else {
 			if msgType != websocket.BinaryMessage {
-				Logger().Debug().Msg("not binary message for log, skip")
+				defaultStatusManager.Warn("socket reader", "not binary message for log, skip")
 				continue
 			}
 
@@ -15,8 +15,12 @@
 package slago
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -40,15 +44,30 @@ type socketWriter struct {
 
 	locker    sync.Mutex
 	conn      *websocket.Conn
+	queueSize int
 	queue     *blockingQueue
 	isStarted bool
+	pending   int64
 
 	remoteUrl   *url.URL
 	reconnDelay time.Duration
 }
 
-// NewSocketWriter create a logging writter via socket.
+// NewSocketWriter create a logging writter via socket. It will exit if
+// any error occurs, use NewSocketWriterE if the error is concerned.
 func NewSocketWriter(options ...func(*SocketWriterOption)) Writer {
+	w, err := NewSocketWriterE(options...)
+	if err != nil {
+		ReportfExit("%v", err)
+	}
+
+	return w
+}
+
+// NewSocketWriterE create a logging writter via socket, an error will be
+// returned if the remote url is missing. The server is connected when the
+// writer is started, so building a writer which is never used won't connect.
+func NewSocketWriterE(options ...func(*SocketWriterOption)) (Writer, error) {
 	opts := &SocketWriterOption{
 		QueueSize:         defaultSocketQueueSize,
 		ReconnectionDelay: defaultReconnectionDelay,
@@ -59,7 +78,7 @@ func NewSocketWriter(options ...func(*SocketWriterOption)) Writer {
 	}
 
 	if opts.RemoteUrl == nil {
-		ReportfExit("socket writer need a remote url")
+		return nil, errors.New("socket writer need a remote url")
 	}
 
 	if opts.QueueSize <= 0 {
@@ -69,33 +88,52 @@ func NewSocketWriter(options ...func(*SocketWriterOption)) Writer {
 		opts.ReconnectionDelay = defaultReconnectionDelay
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(opts.RemoteUrl.String(), nil)
-	if err != nil {
-		ReportfExit("connect socket server error: %v", err)
-	}
-
 	return &socketWriter{
 		encoder:     NewJsonEncoder(),
 		filter:      opts.Filter,
-		conn:        conn,
-		queue:       NewBlockingQueue(opts.QueueSize),
+		queueSize:   opts.QueueSize,
 		reconnDelay: opts.ReconnectionDelay,
 		remoteUrl:   opts.RemoteUrl,
-	}
+	}, nil
 }
 
-func (w *socketWriter) Start() {
+// Start connects the socket server and starts the worker to send events, an
+// error will be returned if the server can't be connected.
+func (w *socketWriter) Start() error {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+
 	if w.isStarted {
-		return
+		return nil
 	}
+	conn, _, err := websocket.DefaultDialer.Dial(w.remoteUrl.String(), nil)
+	if err != nil {
+		return fmt.Errorf("connect socket server error: %v", err)
+	}
+	w.conn = conn
+	// a new queue is used in each start, so the worker of last start won't
+	// take events from this queue
+	w.queue = NewBlockingQueue(w.queueSize)
 	w.isStarted = true
-	go w.startWorker()
+	go w.startWorker(w.queue, conn)
+
+	return nil
 }
 
 func (w *socketWriter) Stop() {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+
+	if !w.isStarted {
+		return
+	}
+	w.isStarted = false
+	// wake up the worker, and the events left won't be sent
+	discarded := w.queue.Close()
+	atomic.AddInt64(&w.pending, -int64(discarded))
 	err := w.conn.Close()
 	if err != nil {
-		Reportf("stop socket writer error: %v", err)
+		defaultStatusManager.Error("socket writer", "stop error: %v", err)
 	}
 }
 
@@ -103,16 +141,22 @@ func (w *socketWriter) Write(p []byte) (int, error) {
 	w.locker.Lock()
 	defer w.locker.Unlock()
 
-	if w.queue.RemainCapacity() <= 2 {
+	if !w.isStarted || w.queue.RemainCapacity() <= 2 {
 		// discard
 		return 0, nil
 	}
 
+	atomic.AddInt64(&w.pending, 1)
 	w.queue.Put(p)
 
 	return len(p), nil
 }
 
+// Drain waits until all the events in queue are sent.
+func (w *socketWriter) Drain(ctx context.Context) error {
+	return drain(ctx, &w.pending)
+}
+
 func (w *socketWriter) Encoder() Encoder {
 	return w.encoder
 }
@@ -121,30 +165,49 @@ func (w *socketWriter) Filter() Filter {
 	return w.filter
 }
 
-func (w *socketWriter) startWorker() {
+// startWorker sends the events in queue until the queue is closed.
+func (w *socketWriter) startWorker(queue *blockingQueue, conn *websocket.Conn) {
 	for {
-		if !w.isStarted {
-			break
+		p, ok := queue.Take()
+		if !ok {
+			return
 		}
 
-		p := w.queue.Take()
-
-		err := w.conn.WriteMessage(websocket.BinaryMessage, p)
+		err := conn.WriteMessage(websocket.BinaryMessage, p)
+		atomic.AddInt64(&w.pending, -1)
 		if err == nil {
 			continue
 		}
 
 		// close first
-		_ = w.conn.Close()
-		Reportf("socket writer write error: %v", err)
+		_ = conn.Close()
+		defaultStatusManager.Error("socket writer", "write error: %v", err)
 
 		// delay before reconnect
 		time.Sleep(w.reconnDelay)
-		conn, _, err := websocket.DefaultDialer.Dial(w.remoteUrl.String(), nil)
+		newConn, _, err := websocket.DefaultDialer.Dial(w.remoteUrl.String(), nil)
 		if err != nil {
-			Reportf("socket writer reconnect error: %v", err)
-		} else {
-			w.conn = conn
+			defaultStatusManager.Error("socket writer", "reconnect error: %v", err)
+			continue
 		}
+		if !w.replaceConn(queue, newConn) {
+			return
+		}
+		conn = newConn
+	}
+}
+
+// replaceConn replaces the connection after reconnected, false will be returned
+// if the worker of given queue has been stopped.
+func (w *socketWriter) replaceConn(queue *blockingQueue, conn *websocket.Conn) bool {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+
+	if !w.isStarted || w.queue != queue {
+		_ = conn.Close()
+		return false
 	}
+	w.conn = conn
+
+	return true
 }
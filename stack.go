@@ -0,0 +1,127 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"reflect"
+	"runtime"
+)
+
+const maxStackDepth = 64
+
+// Stack gets the stack of current goroutine. The frames in slago, bridges and
+// logging frameworks on the top will be skipped, then skips the given frames.
+// Each frame is formatted as function(file:line).
+func Stack(skip int) []string {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+
+	skipPackagesLocker.RLock()
+	defer skipPackagesLocker.RUnlock()
+
+	var start = 0
+	for ; start < n; start++ {
+		fn := runtime.FuncForPC(pcs[start] - 1)
+		if fn == nil || !skipPackages[funcPackage(fn.Name())] {
+			break
+		}
+	}
+	start += skip
+	if start >= n {
+		return nil
+	}
+
+	return formatFrames(pcs[start:n])
+}
+
+// ErrorStack extracts stack from error and its wrapped errors. The errors
+// which implement StackTrace() (such as github.com/pkg/errors) or
+// Callers() []uintptr are supported, and the deepest stack will be used.
+// For multiple wrapped errors, only the first one will be followed.
+func ErrorStack(err error) []string {
+	var pcs []uintptr
+	for err != nil {
+		if p := errorCallers(err); len(p) != 0 {
+			pcs = p
+		}
+
+		// follow the first cause for multiple wrapped errors
+		causes := unwrapErrors(err)
+		if len(causes) == 0 {
+			break
+		}
+		err = causes[0]
+	}
+
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	return formatFrames(pcs)
+}
+
+// AppendErrorStack appends stack extracted from error into record if existed.
+// This is used by slago logger implementations when adding error.
+func AppendErrorStack(r Record, err error) Record {
+	stack := ErrorStack(err)
+	if len(stack) == 0 {
+		return r
+	}
+
+	return r.Strs(StackFieldKey, stack)
+}
+
+func errorCallers(err error) []uintptr {
+	if c, ok := err.(interface{ Callers() []uintptr }); ok {
+		return c.Callers()
+	}
+
+	// the stack trace in github.com/pkg/errors is a slice of uintptr frames,
+	// use reflection to avoid the dependency
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 ||
+		method.Type().NumOut() != 1 {
+		return nil
+	}
+
+	trace := method.Call(nil)[0]
+	if trace.Kind() != reflect.Slice || trace.Type().Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	pcs := make([]uintptr, trace.Len())
+	for i := 0; i < trace.Len(); i++ {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+
+	return pcs
+}
+
+func formatFrames(pcs []uintptr) []string {
+	stack := make([]string, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if len(frame.Function) != 0 {
+			stack = append(stack, formatFrame(frame))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
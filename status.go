@@ -0,0 +1,283 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStatusHistory   = 256
+	defaultRateLimitPeriod = 10 * time.Second
+)
+
+// StatusLevel represents the level of internal status.
+type StatusLevel int8
+
+const (
+	StatusInfo StatusLevel = iota
+	StatusWarn
+	StatusError
+)
+
+func (l StatusLevel) String() string {
+	switch l {
+	case StatusInfo:
+		return "INFO"
+	case StatusWarn:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// Status represents an internal status of slago, such as the errors of
+// writers, rolling policies, removers and bridges.
+type Status struct {
+	Time    time.Time
+	Level   StatusLevel
+	Origin  string
+	Message string
+	// Suppressed is the number of the same errors suppressed by rate limit before this one.
+	Suppressed int
+}
+
+func (s Status) String() string {
+	msg := fmt.Sprintf("slago: [%v] %v: %v", s.Level, s.Origin, s.Message)
+	if s.Suppressed > 0 {
+		msg += fmt.Sprintf(" (%d suppressed)", s.Suppressed)
+	}
+
+	return msg
+}
+
+// StatusListener listens the statuses added into status manager.
+type StatusListener interface {
+	// OnStatus is called when a status is added.
+	OnStatus(s Status)
+}
+
+// StatusListenerFunc is a type adapter that turns a function into a StatusListener.
+type StatusListenerFunc func(s Status)
+
+// OnStatus calls the underlying function.
+func (f StatusListenerFunc) OnStatus(s Status) {
+	f(s)
+}
+
+// NewStderrStatusListener creates a status listener which prints statuses into stderr.
+func NewStderrStatusListener() StatusListener {
+	return StatusListenerFunc(func(s Status) {
+		_, _ = fmt.Fprintln(os.Stderr, s.String())
+	})
+}
+
+// NewWriterStatusListener creates a status listener which writes statuses into
+// slago writer as logging events with logger name "slago".
+func NewWriterStatusListener(w Writer) StatusListener {
+	return StatusListenerFunc(func(s Status) {
+		p, err := json.Marshal(map[string]interface{}{
+			TimestampFieldKey: s.Time.Format(TimestampFormat),
+			LevelFieldKey:     s.Level.String(),
+			LoggerFieldKey:    "slago",
+			MessageFieldKey:   s.Message,
+			"origin":          s.Origin,
+		})
+		if err != nil {
+			return
+		}
+
+		event := makeEvent(p)
+		defer event.recycle()
+		if w.Filter() != nil && w.Filter().Do(event) {
+			return
+		}
+
+		encoded := p
+		if w.Encoder() != nil {
+			if encoded, err = w.Encoder().Encode(event); err != nil {
+				return
+			}
+		}
+		_, _ = w.Write(encoded)
+	})
+}
+
+// StatusManager keeps a bounded history of internal statuses, and notifies
+// the listeners. The same errors are rate limited in a period.
+type StatusManager struct {
+	locker     sync.Mutex
+	history    []Status
+	maxHistory int
+	period     time.Duration
+	listeners  []StatusListener
+	limits     map[string]*statusLimit
+}
+
+// statusLimit represents the rate limit state of the same errors.
+type statusLimit struct {
+	last       time.Time
+	suppressed int
+}
+
+// StatusManagerOption represents available options for status manager.
+type StatusManagerOption struct {
+	MaxHistory      int
+	RateLimitPeriod time.Duration
+}
+
+// NewStatusManager creates a new instance of status manager.
+func NewStatusManager(options ...func(*StatusManagerOption)) *StatusManager {
+	opt := &StatusManagerOption{
+		MaxHistory:      defaultStatusHistory,
+		RateLimitPeriod: defaultRateLimitPeriod,
+	}
+
+	for _, f := range options {
+		f(opt)
+	}
+
+	if opt.MaxHistory <= 0 {
+		opt.MaxHistory = defaultStatusHistory
+	}
+
+	return &StatusManager{
+		history:    make([]Status, 0),
+		maxHistory: opt.MaxHistory,
+		period:     opt.RateLimitPeriod,
+		listeners:  make([]StatusListener, 0),
+		limits:     make(map[string]*statusLimit),
+	}
+}
+
+var defaultStatusManager = newDefaultStatusManager()
+
+func newDefaultStatusManager() *StatusManager {
+	sm := NewStatusManager()
+	sm.AddListener(NewStderrStatusListener())
+	return sm
+}
+
+// DefaultStatusManager gets the status manager used by slago, the statuses
+// will be printed into stderr by default.
+func DefaultStatusManager() *StatusManager {
+	return defaultStatusManager
+}
+
+// AddListener adds a listener to listen statuses.
+func (sm *StatusManager) AddListener(l StatusListener) {
+	sm.locker.Lock()
+	defer sm.locker.Unlock()
+
+	sm.listeners = append(sm.listeners, l)
+}
+
+// ResetListener removes all the listeners.
+func (sm *StatusManager) ResetListener() {
+	sm.locker.Lock()
+	defer sm.locker.Unlock()
+
+	sm.listeners = make([]StatusListener, 0)
+}
+
+// Statuses gets a copy of status history.
+func (sm *StatusManager) Statuses() []Status {
+	sm.locker.Lock()
+	defer sm.locker.Unlock()
+
+	return append([]Status{}, sm.history...)
+}
+
+// Clear clears the status history and rate limit states.
+func (sm *StatusManager) Clear() {
+	sm.locker.Lock()
+	defer sm.locker.Unlock()
+
+	sm.history = make([]Status, 0)
+	sm.limits = make(map[string]*statusLimit)
+}
+
+// Info adds a status with info level.
+func (sm *StatusManager) Info(origin string, format string, args ...interface{}) {
+	sm.add(StatusInfo, origin, format, args...)
+}
+
+// Warn adds a status with warn level.
+func (sm *StatusManager) Warn(origin string, format string, args ...interface{}) {
+	sm.add(StatusWarn, origin, format, args...)
+}
+
+// Error adds a status with error level.
+func (sm *StatusManager) Error(origin string, format string, args ...interface{}) {
+	sm.add(StatusError, origin, format, args...)
+}
+
+func (sm *StatusManager) add(level StatusLevel, origin string,
+	format string, args ...interface{}) {
+	now := time.Now()
+	s := Status{
+		Time:    now,
+		Level:   level,
+		Origin:  origin,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	sm.locker.Lock()
+	// only the repeated errors with the same origin and message are limited
+	if level == StatusError {
+		key := origin + ": " + s.Message
+		if limit, ok := sm.limits[key]; ok && now.Sub(limit.last) < sm.period {
+			limit.suppressed++
+			sm.locker.Unlock()
+			return
+		} else if ok {
+			s.Suppressed = limit.suppressed
+			limit.last = now
+			limit.suppressed = 0
+		} else if sm.period > 0 {
+			sm.pruneLimits(now)
+			sm.limits[key] = &statusLimit{last: now}
+		}
+	}
+
+	if len(sm.history) >= sm.maxHistory {
+		sm.history = append(sm.history[:0], sm.history[1:]...)
+	}
+	sm.history = append(sm.history, s)
+	listeners := sm.listeners
+	sm.locker.Unlock()
+
+	for _, l := range listeners {
+		l.OnStatus(s)
+	}
+}
+
+// pruneLimits removes the expired rate limit states, since the messages of
+// errors may vary. It should be called with locker held.
+func (sm *StatusManager) pruneLimits(now time.Time) {
+	if len(sm.limits) < sm.maxHistory {
+		return
+	}
+
+	for key, limit := range sm.limits {
+		if now.Sub(limit.last) >= sm.period {
+			delete(sm.limits, key)
+		}
+	}
+}
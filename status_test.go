@@ -0,0 +1,76 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestStatus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "status test")
+}
+
+var _ = Describe("status manager", func() {
+	It("history and listeners", func() {
+		sm := NewStatusManager(func(o *StatusManagerOption) {
+			o.MaxHistory = 2
+		})
+		var received []Status
+		sm.AddListener(StatusListenerFunc(func(s Status) {
+			received = append(received, s)
+		}))
+		sm.Info("writer", "info %d", 1)
+		sm.Warn("writer", "warn %d", 2)
+		sm.Error("writer", "error %d", 3)
+
+		Expect(received).To(HaveLen(3))
+		statuses := sm.Statuses()
+		Expect(statuses).To(HaveLen(2))
+		Expect(statuses[0].Message).To(Equal("warn 2"))
+		Expect(statuses[1].String()).To(Equal("slago: [ERROR] writer: error 3"))
+	})
+	It("rate limit", func() {
+		sm := NewStatusManager(func(o *StatusManagerOption) {
+			o.RateLimitPeriod = 50 * time.Millisecond
+		})
+		for i := 0; i < 5; i++ {
+			sm.Error("socket writer", "reconnect error: %v", "refused")
+			sm.Warn("socket writer", "reconnect error: %v", "refused")
+		}
+		sm.Error("socket writer", "reconnect error: %v", "timeout")
+		Expect(sm.Statuses()).To(HaveLen(7))
+
+		time.Sleep(60 * time.Millisecond)
+		sm.Error("socket writer", "reconnect error: %v", "refused")
+		statuses := sm.Statuses()
+		Expect(statuses).To(HaveLen(8))
+		Expect(statuses[7].Suppressed).To(Equal(4))
+		Expect(statuses[7].Message).To(Equal("reconnect error: refused"))
+	})
+	It("writer listener", func() {
+		buf := &bytes.Buffer{}
+		sm := NewStatusManager()
+		sm.AddListener(NewWriterStatusListener(&bufferWriter{buf}))
+		sm.Warn("bridge", "warn")
+		Expect(buf.String()).To(ContainSubstring(`"message":"warn"`))
+		Expect(buf.String()).To(ContainSubstring(`"logger_name":"slago"`))
+	})
+})
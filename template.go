@@ -0,0 +1,87 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"fmt"
+	"strings"
+)
+
+const MessageTemplateFieldKey = "message_template"
+
+// AppendTemplate renders message template with named placeholders such as
+// "user {user} logged in", adds each placeholder as a field with the argument
+// in the same position and adds the template into record, then returns the
+// rendered message. Use "{{" and "}}" to output braces. This is used by slago
+// logger implementations.
+func AppendTemplate(r Record, template string, args ...interface{}) string {
+	// no need to render if the record will not be written
+	if !r.Enabled() {
+		return template
+	}
+
+	var sb strings.Builder
+	var index = 0
+	var names []string
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		if (c == '{' || c == '}') && i+1 < len(template) && template[i+1] == c {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+
+		if c != '{' {
+			sb.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(template[i+1:], '}')
+		if end <= 0 {
+			sb.WriteByte(c)
+			continue
+		}
+
+		name := template[i+1 : i+1+end]
+		i += end + 1
+		if index >= len(args) {
+			// no argument for this placeholder, keep it as is
+			sb.WriteString(template[i-end-1 : i+1])
+			continue
+		}
+
+		arg := args[index]
+		index++
+		sb.WriteString(fmt.Sprint(arg))
+		// the repeated placeholder is rendered but only added as field once
+		if !containsName(names, name) {
+			names = append(names, name)
+			r.Any(name, arg)
+		}
+	}
+	r.Str(MessageTemplateFieldKey, template)
+
+	return sb.String()
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2019-2021 Vincent Cheung (coolingfall@gmail.com).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slago
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTemplate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "template test")
+}
+
+type fieldsRecord struct {
+	noopRecord
+	fields map[string]interface{}
+	keys   []string
+}
+
+func (r *fieldsRecord) Str(key, val string) Record {
+	r.fields[key] = val
+	return r
+}
+
+func (r *fieldsRecord) Any(key string, val interface{}) Record {
+	r.fields[key] = val
+	r.keys = append(r.keys, key)
+	return r
+}
+
+func (r *fieldsRecord) Enabled() bool {
+	return true
+}
+
+var _ = Describe("message template", func() {
+	It("render template", func() {
+		r := &fieldsRecord{fields: make(map[string]interface{})}
+		msg := AppendTemplate(r, "user {user} logged in from {ip} {{ok}}", "bob", 88)
+		Expect(msg).To(Equal("user bob logged in from 88 {ok}"))
+		Expect(r.fields).To(Equal(map[string]interface{}{
+			"user":                  "bob",
+			"ip":                    88,
+			MessageTemplateFieldKey: "user {user} logged in from {ip} {{ok}}",
+		}))
+	})
+	It("render template without enough arguments", func() {
+		r := &fieldsRecord{fields: make(map[string]interface{})}
+		msg := AppendTemplate(r, "{a} and {b} and {", 1)
+		Expect(msg).To(Equal("1 and {b} and {"))
+		Expect(r.fields).To(HaveKeyWithValue("a", 1))
+		Expect(r.fields).NotTo(HaveKey("b"))
+	})
+	It("render repeated placeholder", func() {
+		r := &fieldsRecord{fields: make(map[string]interface{})}
+		msg := AppendTemplate(r, "{id} retried as {id} by {user}", 1, 2, "bob")
+		Expect(msg).To(Equal("1 retried as 2 by bob"))
+		Expect(r.keys).To(Equal([]string{"id", "user"}))
+		Expect(r.fields).To(HaveKeyWithValue("id", 1))
+	})
+	It("encode template in json", func() {
+		event := makeEvent([]byte(`{"level":"INFO","time":"2019-12-27T10:40:14.465199844+08:00",` +
+			`"message":"user bob","message_template":"user {user}","user":"bob"}`))
+		out, err := NewJsonEncoder().Encode(event)
+		Expect(err).To(BeNil())
+		Expect(string(out)).To(ContainSubstring(
+			`"message":"user bob","message_template":"user {user}","user":"bob"}`))
+	})
+})
@@ -35,6 +35,7 @@ type Writer interface {
 // This writer is used as output which will implement SlaLogger.
 type MultiWriter struct {
 	locker       sync.Mutex
+	guard        writingGuard
 	writers      []Writer
 	asyncWriters []Writer
 }
@@ -47,18 +48,25 @@ func NewMultiWriter() *MultiWriter {
 	}
 }
 
-// AddWriter adds a slago writer into multi writer.
+// AddWriter adds a slago writer into multi writer. The writer will be
+// ignored if it can't be started.
 func (mw *MultiWriter) AddWriter(writers ...Writer) {
 	for _, w := range writers {
 		if lc, ok := w.(Lifecycle); ok {
-			lc.Start()
+			if err := lc.Start(); err != nil {
+				defaultStatusManager.Error("writer", "start writer error: %v", err)
+				continue
+			}
 		}
 
+		// the writer is started without lock held, since it may log
+		mw.locker.Lock()
 		if _, ok := w.(*asyncWriter); ok {
 			mw.asyncWriters = append(mw.asyncWriters, w)
 		} else {
 			mw.writers = append(mw.writers, w)
 		}
+		mw.locker.Unlock()
 	}
 }
 
@@ -67,7 +75,7 @@ func (mw *MultiWriter) Reset() {
 	mw.locker.Lock()
 	defer mw.locker.Unlock()
 
-	for _, w := range mw.writers {
+	for _, w := range append(mw.writers, mw.asyncWriters...) {
 		if lc, ok := w.(Lifecycle); ok {
 			lc.Stop()
 		}
@@ -76,7 +84,44 @@ func (mw *MultiWriter) Reset() {
 	mw.asyncWriters = make([]Writer, 0)
 }
 
+// swap replaces all the writers without starting or stopping them, and
+// returns the writers replaced.
+func (mw *MultiWriter) swap(writers []Writer) []Writer {
+	mw.locker.Lock()
+	defer mw.locker.Unlock()
+
+	old := append(mw.writers, mw.asyncWriters...)
+	mw.writers = make([]Writer, 0)
+	mw.asyncWriters = make([]Writer, 0)
+	for _, w := range writers {
+		if _, ok := w.(*asyncWriter); ok {
+			mw.asyncWriters = append(mw.asyncWriters, w)
+		} else {
+			mw.writers = append(mw.writers, w)
+		}
+	}
+
+	return old
+}
+
+// Write writes the event into all writers. The events emitted inside writers
+// on the same goroutine will be dropped to avoid recursion.
+//
+//go:noinline
 func (mw *MultiWriter) Write(p []byte) (n int, err error) {
+	if mw.guard.recursive(multiWriterEntry) {
+		dropRecursive(p)
+		return len(p), nil
+	}
+	mw.guard.enter()
+	defer mw.guard.exit()
+
+	return mw.write(p)
+}
+
+// write writes the event into all writers without recursion check, this is
+// used for the writers nested in other writers.
+func (mw *MultiWriter) write(p []byte) (n int, err error) {
 	mw.locker.Lock()
 	defer mw.locker.Unlock()
 
@@ -106,11 +151,20 @@ func (mw *MultiWriter) writeNormal(p []byte) (n int, err error) {
 		return 0, nil
 	}
 
-	event := makeEvent(p)
-	defer event.recycle()
+	// the event is only made when needed by filter or encoder
+	var event *LogEvent
+	defer func() {
+		if event != nil {
+			event.recycle()
+		}
+	}()
 	for _, w := range mw.writers {
+		if event == nil && (w.Filter() != nil || w.Encoder() != nil) {
+			event = makeEvent(p)
+		}
+
 		if w.Filter() != nil && w.Filter().Do(event) {
-			return
+			continue
 		}
 
 		encoded := p
@@ -120,7 +174,9 @@ func (mw *MultiWriter) writeNormal(p []byte) (n int, err error) {
 				return 0, err
 			}
 		}
-		n, err = w.Write(encoded)
+		if n, err = w.Write(encoded); err != nil {
+			defaultStatusManager.Error("writer", "write error: %v", err)
+		}
 	}
 
 	return len(p), nil